@@ -1,16 +1,128 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"net/url"
 	"os" // Import os package
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	_ "time/tzdata" // Time zones for display, even on hosts without zoneinfo
 
 	"masax-mcp/internal/masax" // Import masax client package
 	"masax-mcp/internal/mcp"
+	"masax-mcp/internal/metrics"
 
 	"github.com/joho/godotenv"           // Import godotenv
 	"github.com/mark3labs/mcp-go/server" // Import server package
 )
 
+const (
+	defaultTransport = "stdio"
+	defaultSSEAddr   = ":8080"
+	defaultGrace     = 10 * time.Second
+
+	// transportShutdownTimeout bounds closing the transport's connections
+	// once in-flight requests have finished or been cancelled.
+	transportShutdownTimeout = 5 * time.Second
+)
+
+// envOrDefault returns the value of the environment variable key, or def if unset.
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// intEnvOrDefault parses the environment variable key as an integer, or
+// returns def if unset.
+func intEnvOrDefault(key string, def int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	return strconv.Atoi(v)
+}
+
+// durationEnv parses the environment variable key as a duration, e.g. "5s",
+// returning 0 if it's unset.
+func durationEnv(key string) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err == nil && d <= 0 {
+		err = fmt.Errorf("must be positive, got %q", v)
+	}
+	return d, err
+}
+
+// validateBaseURL checks that raw is an absolute http or https URL.
+func validateBaseURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https, got %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("missing host in %q", raw)
+	}
+	return nil
+}
+
+// toolDescriptionOptions parses a JSON object mapping tool names to
+// replacement descriptions, e.g. {"masa_x_search": "..."}. Empty input
+// yields no options.
+func toolDescriptionOptions(raw string) ([]mcp.ServerOption, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var descriptions map[string]string
+	if err := json.Unmarshal([]byte(raw), &descriptions); err != nil {
+		return nil, fmt.Errorf("expected a JSON object of tool names to descriptions: %w", err)
+	}
+	opts := make([]mcp.ServerOption, 0, len(descriptions))
+	for name, description := range descriptions {
+		opts = append(opts, mcp.WithToolDescription(name, description))
+	}
+	return opts, nil
+}
+
+// loadAPIKey returns MASA_API_KEY, or the contents of the file named by
+// MASA_API_KEY_FILE (e.g. a mounted Kubernetes secret) with surrounding
+// whitespace trimmed.
+func loadAPIKey() (string, error) {
+	if key := os.Getenv("MASA_API_KEY"); key != "" {
+		return key, nil
+	}
+	path := os.Getenv("MASA_API_KEY_FILE")
+	if path == "" {
+		return "", fmt.Errorf("neither MASA_API_KEY nor MASA_API_KEY_FILE environment variable is set")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read MASA_API_KEY_FILE: %w", err)
+	}
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("MASA_API_KEY_FILE %q is empty", path)
+	}
+	return key, nil
+}
+
 func main() {
 	// Load .env file. Handle errors, but maybe continue if not found?
 	err := godotenv.Load() // Load .env from current directory
@@ -18,26 +130,222 @@ func main() {
 		log.Println("Warning: Could not load .env file:", err)
 	}
 
-	// Get API key from environment
-	apiKey := os.Getenv("MASA_API_KEY")
-	if apiKey == "" {
-		log.Fatalf("Error: MASA_API_KEY environment variable not set.")
+	// Parse flags; environment variables supply the defaults
+	envDefaultMaxResults, err := intEnvOrDefault("MASA_DEFAULT_MAX_RESULTS", 20)
+	if err != nil {
+		log.Fatalf("Error: invalid MASA_DEFAULT_MAX_RESULTS: %v", err)
+	}
+	transport := flag.String("transport", envOrDefault("MCP_TRANSPORT", defaultTransport), `Transport to serve on: "stdio" or "sse" (env MCP_TRANSPORT)`)
+	addr := flag.String("addr", envOrDefault("MCP_ADDR", defaultSSEAddr), "Listen address for the sse transport (env MCP_ADDR)")
+	enableMetrics := flag.Bool("metrics", os.Getenv("MCP_METRICS") == "true", "Expose Prometheus metrics on /metrics with the sse transport (env MCP_METRICS=true)")
+	grace := flag.Duration("shutdown-grace", defaultGrace, "How long to wait for in-flight requests on shutdown")
+	resultBudget := flag.Int("result-budget", 0, "Approximate byte limit on search results embedded in a tool response; 0 uses the default")
+	maxResultsLimit := flag.Int("max-results-limit", 0, "Largest max_results the tools accept; larger searches are fetched a page at a time, with progress notifications; 0 uses the default")
+	defaultMaxResults := flag.Int("default-max-results", envDefaultMaxResults, "max_results applied when a tool call omits it; 0 leaves the count to the API (env MASA_DEFAULT_MAX_RESULTS)")
+	disabledTools := flag.String("disabled-tools", os.Getenv("MCP_DISABLED_TOOLS"), "Comma-separated tools not to expose, e.g. masa_x_search_batch (env MCP_DISABLED_TOOLS)")
+	exportDir := flag.String("export-dir", os.Getenv("MCP_EXPORT_DIR"), "Directory the masa_x_export tool writes result files to; the tool is disabled when unset (env MCP_EXPORT_DIR)")
+	webhookAllowlist := flag.String("webhook-allowlist", os.Getenv("MCP_WEBHOOK_ALLOWLIST"), "Comma-separated hosts, e.g. hooks.example.com or *.example.com, that masa_x_search_webhook may POST results to; the tool is disabled when unset (env MCP_WEBHOOK_ALLOWLIST)")
+	flag.Parse()
+	if *transport != "stdio" && *transport != "sse" {
+		log.Fatalf("Error: unknown transport %q (expected \"stdio\" or \"sse\")", *transport)
+	}
+	if *enableMetrics && *transport != "sse" {
+		log.Fatalf("Error: --metrics requires the sse transport")
+	}
+	if *defaultMaxResults < 0 {
+		log.Fatalf("Error: --default-max-results must not be negative, got %d", *defaultMaxResults)
+	}
+
+	// Get API key from the environment or a secret file
+	apiKey, err := loadAPIKey()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
 	}
 
-	// Create Masa X client
-	masaClient, err := masax.NewClient(apiKey)
+	// Optional endpoint overrides, e.g. to target production instead of dev
+	baseURL := os.Getenv("MASA_BASE_URL")
+	if baseURL != "" {
+		if err := validateBaseURL(baseURL); err != nil {
+			log.Fatalf("Error: invalid MASA_BASE_URL: %v", err)
+		}
+	}
+
+	// How the key is sent, e.g. "header:X-API-Key" for gateways that don't
+	// accept a bearer token
+	authScheme, err := masax.ParseAuthScheme(os.Getenv("MASA_AUTH_SCHEME"))
+	if err != nil {
+		log.Fatalf("Error: invalid MASA_AUTH_SCHEME: %v", err)
+	}
+
+	// TLS policy, e.g. for an API behind an internal CA
+	tlsMinVersion, err := masax.ParseTLSVersion(os.Getenv("MASA_TLS_MIN_VERSION"))
+	if err != nil {
+		log.Fatalf("Error: invalid MASA_TLS_MIN_VERSION: %v", err)
+	}
+
+	// Connection timeouts, e.g. "5s", separate from the overall per-attempt
+	// timeout so an unreachable API fails fast without cutting off slow
+	// downloads of large responses
+	connectTimeout, err := durationEnv("MASA_CONNECT_TIMEOUT")
+	if err != nil {
+		log.Fatalf("Error: invalid MASA_CONNECT_TIMEOUT: %v", err)
+	}
+	tlsHandshakeTimeout, err := durationEnv("MASA_TLS_HANDSHAKE_TIMEOUT")
+	if err != nil {
+		log.Fatalf("Error: invalid MASA_TLS_HANDSHAKE_TIMEOUT: %v", err)
+	}
+
+	// How engagement is weighed when ranking results, e.g.
+	// "likes=1,retweets=2,replies=1.5"
+	weights, err := masax.ParseEngagementWeights(os.Getenv("MASA_ENGAGEMENT_WEIGHTS"))
+	if err != nil {
+		log.Fatalf("Error: invalid MASA_ENGAGEMENT_WEIGHTS: %v", err)
+	}
+
+	// Synonyms for searches that ask for query expansion, e.g.
+	// "AI=artificial intelligence|machine learning;BTC=bitcoin"
+	synonyms, err := masax.ParseSynonyms(os.Getenv("MASA_SYNONYMS"))
+	if err != nil {
+		log.Fatalf("Error: invalid MASA_SYNONYMS: %v", err)
+	}
+
+	// Create Masa X client. Caching lets resource reads reuse the response the
+	// search tool just fetched instead of spending API quota on a repeat call,
+	// and the circuit breaker fails tool calls fast while the API is down.
+	clientOpts := []masax.ClientOption{
+		masax.WithBaseURL(baseURL),                          // No-op when unset
+		masax.WithSearchPath(os.Getenv("MASA_SEARCH_PATH")), // No-op when unset
+		masax.WithLookupPath(os.Getenv("MASA_LOOKUP_PATH")), // Enables masa_x_get_tweet API lookups
+		masax.WithAPIVersion(os.Getenv("MASA_API_VERSION")), // No-op when unset
+		masax.WithAuthScheme(authScheme),
+		masax.WithSearchMethod(os.Getenv("MASA_SEARCH_METHOD")),
+		masax.WithConnectTimeout(connectTimeout),
+		masax.WithTLSHandshakeTimeout(tlsHandshakeTimeout),
+		masax.WithEngagementWeights(weights),
+		masax.WithSynonyms(synonyms),                // No-op when unset
+		masax.WithCAFile(os.Getenv("MASA_CA_FILE")), // No-op when unset
+		masax.WithCache(100, 5*time.Minute),
+		masax.WithCircuitBreaker(5, 30*time.Second),
+		masax.WithDefaultDeadline(time.Minute), // MCP requests carry no deadline of their own
+	}
+
+	// Fail searches whose responses lack required fields instead of
+	// returning results with empty fields
+	if os.Getenv("MASA_STRICT_VALIDATION") == "true" {
+		clientOpts = append(clientOpts, masax.WithStrictValidation())
+	}
+
+	// Plain http base URLs send the API key unencrypted, so they need an
+	// explicit opt-in unless the host is loopback
+	if os.Getenv("MASA_ALLOW_INSECURE") == "true" {
+		clientOpts = append(clientOpts, masax.WithAllowInsecure())
+	}
+
+	if tlsMinVersion != 0 {
+		clientOpts = append(clientOpts, masax.WithTLSMinVersion(tlsMinVersion))
+	}
+
+	// Metrics are opt-in and only served over HTTP
+	var searchMetrics *metrics.SearchMetrics
+	if *enableMetrics {
+		searchMetrics = metrics.NewSearchMetrics()
+		clientOpts = append(clientOpts, masax.WithSearchHook(searchMetrics.Observe))
+	}
+
+	masaClient, err := masax.NewClient(apiKey, clientOpts...)
+	if errors.Is(err, masax.ErrInsecureBaseURL) {
+		log.Fatalf("Error: %v (set MASA_ALLOW_INSECURE=true to allow it)", err)
+	}
 	if err != nil {
 		log.Fatalf("Failed to create Masa X client: %v", err)
 	}
 
 	// Initialize MCP server, passing the client
-	mcpServer, err := mcp.NewServer(masaClient)
+	serverOpts := []mcp.ServerOption{
+		mcp.WithResultBudget(*resultBudget),
+		mcp.WithMaxResultsLimit(*maxResultsLimit),
+		mcp.WithDefaultMaxResults(*defaultMaxResults),
+		mcp.WithDisabledTools(strings.Split(*disabledTools, ",")...),
+		mcp.WithExportDir(*exportDir),
+		mcp.WithWebhookAllowlist(strings.Split(*webhookAllowlist, ",")...),
+	}
+	descriptionOpts, err := toolDescriptionOptions(os.Getenv("MCP_TOOL_DESCRIPTIONS"))
+	if err != nil {
+		log.Fatalf("Error: invalid MCP_TOOL_DESCRIPTIONS: %v", err)
+	}
+	serverOpts = append(serverOpts, descriptionOpts...)
+
+	mcpServer, err := mcp.NewServer(masaClient, serverOpts...)
 	if err != nil {
 		log.Fatalf("Failed to create MCP server: %v", err)
 	}
 
-	// Start the server using the server package function
-	if err := server.ServeStdio(mcpServer.MCPServer); err != nil {
-		log.Fatalf("Server error: %v", err)
+	// Cancelled on SIGINT/SIGTERM
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// serveCtx is passed to the transport and outlives the signal so in-flight
+	// searches can finish; it's cancelled once they have, or the grace period ends.
+	serveCtx, cancelServe := context.WithCancel(context.Background())
+	defer cancelServe()
+
+	// Start the server on the selected transport
+	errCh := make(chan error, 1)
+	var shutdownTransport func(context.Context) error
+	switch *transport {
+	case "stdio":
+		stdioServer := server.NewStdioServer(mcpServer.MCPServer)
+		stdioServer.SetErrorLogger(log.New(os.Stderr, "", log.LstdFlags))
+		go func() { errCh <- stdioServer.Listen(serveCtx, os.Stdin, os.Stdout) }()
+	case "sse":
+		// Serve SSE from our own mux so /metrics can share the listener.
+		// Passing the http.Server to the SSE server lets its Shutdown close it.
+		// Tool calls run on their request's context, so deriving it from
+		// serveCtx lets cancelServe stop them.
+		mux := http.NewServeMux()
+		httpServer := &http.Server{
+			Addr:        *addr,
+			Handler:     mux,
+			BaseContext: func(net.Listener) context.Context { return serveCtx },
+		}
+		sseServer := server.NewSSEServer(mcpServer.MCPServer, server.WithHTTPServer(httpServer))
+		mux.Handle("/", sseServer)
+		if searchMetrics != nil {
+			mux.Handle("/metrics", searchMetrics.Handler())
+		}
+		log.Printf("Serving MCP over SSE on %s", *addr)
+		go func() { errCh <- httpServer.ListenAndServe() }()
+		shutdownTransport = sseServer.Shutdown
+	}
+
+	select {
+	case err := <-errCh:
+		// The transport stopped on its own, e.g. stdin was closed
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Server error: %v", err)
+		}
+		return
+	case <-sigCtx.Done():
+	}
+	stop() // A second signal kills the process immediately
+
+	log.Printf("Shutdown signal received; waiting up to %s for in-flight requests", *grace)
+	graceCtx, cancelGrace := context.WithTimeout(context.Background(), *grace)
+	defer cancelGrace()
+
+	if err := mcpServer.Shutdown(graceCtx); err != nil {
+		log.Printf("In-flight requests still running after %s; cancelling them", *grace)
+	}
+	cancelServe()
+
+	if shutdownTransport != nil {
+		log.Println("Closing transport connections")
+		// graceCtx may have expired already, so closing gets its own timeout
+		closeCtx, cancelClose := context.WithTimeout(context.Background(), transportShutdownTimeout)
+		defer cancelClose()
+		if err := shutdownTransport(closeCtx); err != nil {
+			log.Printf("Transport shutdown error: %v", err)
+		}
 	}
+	log.Println("Shutdown complete")
 }
@@ -0,0 +1,120 @@
+package masax
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Authentication scheme types.
+const (
+	AuthTypeBearer = "bearer" // Authorization: Bearer <key>
+	AuthTypeHeader = "header" // <Header>: <key>
+)
+
+// defaultAuthHeader carries the key for AuthTypeHeader if no header is named.
+const defaultAuthHeader = "X-Api-Key"
+
+// AuthScheme selects how the API key is sent.
+type AuthScheme struct {
+	Type   string // AuthTypeBearer or AuthTypeHeader
+	Header string // Header carrying the raw key for AuthTypeHeader; X-Api-Key if empty
+}
+
+// BearerAuth sends the key as "Authorization: Bearer <key>". It is the
+// default.
+func BearerAuth() AuthScheme {
+	return AuthScheme{Type: AuthTypeBearer}
+}
+
+// HeaderAuth sends the raw key in the named header, e.g. X-API-Key, as some
+// API gateways expect.
+func HeaderAuth(name string) AuthScheme {
+	return AuthScheme{Type: AuthTypeHeader, Header: name}
+}
+
+// ParseAuthScheme parses "bearer", "header" or "header:<name>", e.g.
+// "header:X-API-Key". An empty string yields the default, BearerAuth.
+func ParseAuthScheme(s string) (AuthScheme, error) {
+	kind, name, _ := strings.Cut(strings.TrimSpace(s), ":")
+	switch strings.ToLower(kind) {
+	case "", AuthTypeBearer:
+		if name != "" {
+			return AuthScheme{}, fmt.Errorf("invalid auth scheme %q: bearer takes no header name", s)
+		}
+		return BearerAuth(), nil
+	case AuthTypeHeader:
+		scheme := HeaderAuth(strings.TrimSpace(name))
+		return scheme, scheme.validate()
+	}
+	return AuthScheme{}, fmt.Errorf("invalid auth scheme %q: must be %q or %q", s, AuthTypeBearer, AuthTypeHeader+"[:<name>]")
+}
+
+// validate checks the scheme type and header name.
+func (a AuthScheme) validate() error {
+	switch a.Type {
+	case AuthTypeBearer:
+		return nil
+	case AuthTypeHeader:
+		if a.Header == "" {
+			return nil
+		}
+		if !validHeaderName(a.Header) {
+			return fmt.Errorf("invalid auth header name %q", a.Header)
+		}
+		if protectedHeaders[http.CanonicalHeaderKey(a.Header)] {
+			return fmt.Errorf("invalid auth header name %q: reserved by the client", a.Header)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid auth scheme type %q: must be %q or %q", a.Type, AuthTypeBearer, AuthTypeHeader)
+}
+
+// validHeaderName reports whether name is a valid HTTP header field name
+// (an RFC 9110 token).
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r > '~' || !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("!#$%&'*+-.^_`|~", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// headerName returns the canonical header that carries the key.
+func (a AuthScheme) headerName() string {
+	if a.Type != AuthTypeHeader {
+		return "Authorization"
+	}
+	if a.Header == "" {
+		return defaultAuthHeader
+	}
+	return http.CanonicalHeaderKey(a.Header)
+}
+
+// WithAuthScheme selects how the API key is sent: BearerAuth (the default)
+// or HeaderAuth. The key's header can't be overridden with WithHeader and is
+// redacted from logs and dry runs. NewClient returns an error for an
+// unknown type or invalid header name.
+func WithAuthScheme(scheme AuthScheme) ClientOption {
+	return func(c *Client) {
+		if err := scheme.validate(); err != nil {
+			c.setOptionErr(err)
+			return
+		}
+		c.auth = scheme
+	}
+}
+
+// setAuth sets the API key header on req.
+func (c *Client) setAuth(req *http.Request) {
+	key := c.currentAPIKey() // Read under the key lock
+	if c.auth.Type == AuthTypeHeader {
+		req.Header.Set(c.auth.headerName(), key)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+key)
+}
@@ -0,0 +1,52 @@
+package masax
+
+import "sort"
+
+// AuthorStats summarizes one author's posts within a set of results.
+type AuthorStats struct {
+	AuthorID      string        `json:"author_id"`
+	PostCount     int           `json:"post_count"`
+	PublicMetrics PublicMetrics `json:"public_metrics"`
+	Engagement    int           `json:"engagement"`
+}
+
+// TopAuthors groups items by AuthorID and returns the top n authors (all of
+// them if n <= 0) ordered by total EngagementScore, then post count, then
+// author ID. Items without an AuthorID are skipped.
+func TopAuthors(items []SearchResult, n int) []AuthorStats {
+	byAuthor := make(map[string]*AuthorStats)
+	for _, item := range items {
+		if item.AuthorID == "" {
+			continue
+		}
+		a, ok := byAuthor[item.AuthorID]
+		if !ok {
+			a = &AuthorStats{AuthorID: item.AuthorID}
+			byAuthor[item.AuthorID] = a
+		}
+		a.PostCount++
+		a.PublicMetrics.RetweetCount += item.PublicMetrics.RetweetCount
+		a.PublicMetrics.ReplyCount += item.PublicMetrics.ReplyCount
+		a.PublicMetrics.LikeCount += item.PublicMetrics.LikeCount
+		a.PublicMetrics.QuoteCount += item.PublicMetrics.QuoteCount
+		a.Engagement += EngagementScore(item.PublicMetrics)
+	}
+
+	authors := make([]AuthorStats, 0, len(byAuthor))
+	for _, a := range byAuthor {
+		authors = append(authors, *a)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Engagement != authors[j].Engagement {
+			return authors[i].Engagement > authors[j].Engagement
+		}
+		if authors[i].PostCount != authors[j].PostCount {
+			return authors[i].PostCount > authors[j].PostCount
+		}
+		return authors[i].AuthorID < authors[j].AuthorID
+	})
+	if n > 0 && len(authors) > n {
+		authors = authors[:n]
+	}
+	return authors
+}
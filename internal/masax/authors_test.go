@@ -0,0 +1,63 @@
+package masax
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopAuthors(t *testing.T) {
+	post := func(author string, likes, retweets, replies, quotes int) SearchResult {
+		return SearchResult{AuthorID: author, PublicMetrics: PublicMetrics{
+			LikeCount: likes, RetweetCount: retweets, ReplyCount: replies, QuoteCount: quotes,
+		}}
+	}
+	items := []SearchResult{
+		post("alice", 10, 1, 0, 0),
+		post("bob", 30, 0, 0, 0),
+		post("alice", 5, 2, 3, 4),
+		post("", 1000, 0, 0, 0), // No author; skipped
+		post("carol", 15, 0, 0, 0),
+		post("dave", 10, 5, 0, 0),
+		post("carol", 0, 0, 0, 0),
+	}
+
+	want := []AuthorStats{
+		{AuthorID: "bob", PostCount: 1, PublicMetrics: PublicMetrics{LikeCount: 30}, Engagement: 30},
+		{AuthorID: "alice", PostCount: 2, PublicMetrics: PublicMetrics{LikeCount: 15, RetweetCount: 3, ReplyCount: 3, QuoteCount: 4}, Engagement: 25},
+		// Tied on engagement with dave; more posts wins
+		{AuthorID: "carol", PostCount: 2, PublicMetrics: PublicMetrics{LikeCount: 15}, Engagement: 15},
+		{AuthorID: "dave", PostCount: 1, PublicMetrics: PublicMetrics{LikeCount: 10, RetweetCount: 5}, Engagement: 15},
+	}
+	if got := TopAuthors(items, 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("TopAuthors =\n%+v\nwant\n%+v", got, want)
+	}
+	if got := TopAuthors(items, 2); !reflect.DeepEqual(got, want[:2]) {
+		t.Errorf("TopAuthors(2) = %+v, want %+v", got, want[:2])
+	}
+	if got := TopAuthors(items, 10); len(got) != len(want) {
+		t.Errorf("TopAuthors(10) returned %d authors, want %d", len(got), len(want))
+	}
+}
+
+func TestTopAuthorsTiedOnEverything(t *testing.T) {
+	items := []SearchResult{
+		{AuthorID: "zed", PublicMetrics: PublicMetrics{LikeCount: 1}},
+		{AuthorID: "amy", PublicMetrics: PublicMetrics{RetweetCount: 1}},
+		{AuthorID: "max", PublicMetrics: PublicMetrics{QuoteCount: 1}},
+	}
+	for i := 0; i < 10; i++ { // Map iteration order varies between runs
+		got := TopAuthors(items, 0)
+		if len(got) != 3 || got[0].AuthorID != "amy" || got[1].AuthorID != "max" || got[2].AuthorID != "zed" {
+			t.Fatalf("TopAuthors = %+v, want amy, max, zed", got)
+		}
+	}
+}
+
+func TestTopAuthorsEmpty(t *testing.T) {
+	for _, items := range [][]SearchResult{nil, {}, {{ID: "1", Text: "no author"}}} {
+		got := TopAuthors(items, 5)
+		if got == nil || len(got) != 0 {
+			t.Errorf("TopAuthors(%+v) = %#v, want an empty, non-nil slice", items, got)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package masax
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TimeWindow is a time range for SearchWindows. Start is inclusive and End
+// exclusive; a zero time leaves that side open, as with WithTimeRange.
+type TimeWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Query returns query restricted to the window's dates with since: and
+// until: operators, from QueryBuilder.Between, for callers that can't send
+// the exact range. It's query itself if the window is open on both sides.
+func (w TimeWindow) Query(query string) (string, error) {
+	if w.Start.IsZero() && w.End.IsZero() {
+		return query, nil
+	}
+	return NewQuery().Raw(query).Between(w.Start, w.End).Build()
+}
+
+// SearchBatch runs a search for each query using at most concurrency
+// parallel requests. Results and errors are returned in the same order as
+// queries; a failed query leaves a non-nil error at its index without
+// affecting the others. Its response is nil, or holds partial results if
+// Search returned them with the error. Cancelling ctx aborts queries that
+// haven't started yet with ctx.Err().
+func (c *Client) SearchBatch(ctx context.Context, queries []string, maxResults int, concurrency int, opts ...SearchOption) ([]*SearchResponse, []error) {
+	return runBatch(ctx, len(queries), concurrency, func(i int) (*SearchResponse, error) {
+		return c.Search(ctx, queries[i], maxResults, opts...)
+	})
+}
+
+// SearchWindows runs query once per time window, as SearchBatch runs its
+// queries: with at most concurrency parallel requests, returning results
+// and errors in the order of windows. Each window's range replaces any
+// WithTimeRange in opts.
+func (c *Client) SearchWindows(ctx context.Context, query string, windows []TimeWindow, maxResults int, concurrency int, opts ...SearchOption) ([]*SearchResponse, []error) {
+	return runBatch(ctx, len(windows), concurrency, func(i int) (*SearchResponse, error) {
+		windowOpts := append(append([]SearchOption(nil), opts...), WithTimeRange(windows[i].Start, windows[i].End))
+		return c.Search(ctx, query, maxResults, windowOpts...)
+	})
+}
+
+// runBatch calls search for each index below n using at most concurrency
+// goroutines, collecting the results and errors by index. Indexes not yet
+// started when ctx is cancelled get ctx.Err().
+func runBatch(ctx context.Context, n int, concurrency int, search func(i int) (*SearchResponse, error)) ([]*SearchResponse, []error) {
+	results := make([]*SearchResponse, n)
+	errs := make([]error, n)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrency, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = search(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			errs[i] = ctx.Err() // Never handed to a worker
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, errs
+}
+
+// BatchHandle controls a batch started with StartSearchBatch.
+type BatchHandle struct {
+	cancel  context.CancelFunc
+	done    chan struct{}
+	results []*SearchResponse // Set before done is closed
+	errs    []error
+}
+
+// StartSearchBatch runs SearchBatch in the background and returns at once
+// with a handle to wait for or cancel it.
+func (c *Client) StartSearchBatch(ctx context.Context, queries []string, maxResults int, concurrency int, opts ...SearchOption) *BatchHandle {
+	ctx, cancel := context.WithCancel(ctx)
+	h := &BatchHandle{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(h.done)
+		defer cancel() // Release the context once the batch is over
+		h.results, h.errs = c.SearchBatch(ctx, queries, maxResults, concurrency, opts...)
+	}()
+	return h
+}
+
+// Cancel stops the batch: queries not yet started fail with
+// context.Canceled without being sent, and in-flight ones are cancelled.
+// Queries already finished keep their results. Cancel doesn't wait for the
+// batch to stop; call Wait for that. Cancelling a finished batch does
+// nothing.
+func (h *BatchHandle) Cancel() {
+	h.cancel()
+}
+
+// Done returns a channel that's closed when the batch has finished, after
+// which Wait returns at once.
+func (h *BatchHandle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Wait blocks until the batch has finished and returns its results and
+// errors, as SearchBatch does. It may be called any number of times.
+func (h *BatchHandle) Wait() ([]*SearchResponse, []error) {
+	<-h.done
+	return h.results, h.errs
+}
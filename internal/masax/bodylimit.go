@@ -0,0 +1,40 @@
+package masax
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// defaultMaxResponseBytes caps response bodies unless overridden with
+// WithMaxResponseBytes. A full page of results is well under 1 MiB.
+const defaultMaxResponseBytes = 32 << 20 // 32 MiB
+
+// ErrResponseTooLarge is returned, wrapped, when a response body exceeds the
+// limit set with WithMaxResponseBytes.
+var ErrResponseTooLarge = errors.New("masa X API: response body too large")
+
+// WithMaxResponseBytes limits response bodies to n bytes (32 MiB unless
+// overridden), both as received and after decompression, so a misbehaving
+// endpoint can't exhaust memory. Larger responses fail with an error
+// wrapping ErrResponseTooLarge and aren't retried.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxResponseBytes = n
+		}
+	}
+}
+
+// readLimited reads r to EOF, failing with ErrResponseTooLarge once more than
+// limit bytes have been read.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, limit)
+	}
+	return data, nil
+}
@@ -0,0 +1,161 @@
+package masax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the API while the circuit
+// breaker configured with WithCircuitBreaker is open.
+var ErrCircuitOpen = errors.New("masa X API: circuit breaker open")
+
+// CircuitState is the state of the client's circuit breaker.
+type CircuitState string
+
+const (
+	// CircuitClosed lets all searches through.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen fails searches immediately with ErrCircuitOpen.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen lets a single probe search through; its outcome closes
+	// or re-opens the circuit.
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// WithCircuitBreaker stops calling the API after threshold consecutive
+// failures, failing searches with ErrCircuitOpen for cooldown. After the
+// cooldown one search is let through as a probe: success closes the
+// circuit, failure opens it for another cooldown.
+//
+// Only signs of an unhealthy API count as failures: network errors,
+// timeouts and 5xx responses. Other API errors count as successes, and
+// searches the caller cancels are ignored. Cached results and Ping bypass
+// the breaker. By default no breaker is used.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) ClientOption {
+	return func(c *Client) {
+		if threshold < 1 || cooldown <= 0 {
+			return
+		}
+		c.breaker = &circuitBreaker{threshold: threshold, cooldown: cooldown, state: CircuitClosed, now: c.now}
+	}
+}
+
+// CircuitState reports the circuit breaker's state. It is CircuitClosed if
+// no breaker is configured.
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	return c.breaker.currentState()
+}
+
+// circuitBreaker counts consecutive failures. It is safe for concurrent use.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int       // Consecutive failures while closed
+	openedAt time.Time // When the circuit last opened
+	probing  bool      // A half-open probe is in flight
+}
+
+// allow reports whether a search may be sent, returning an error wrapping
+// ErrCircuitOpen if not.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen {
+		remaining := b.cooldown - b.now().Sub(b.openedAt)
+		if remaining > 0 {
+			return fmt.Errorf("%w; retry in %s", ErrCircuitOpen, remaining.Round(time.Millisecond))
+		}
+		b.state = CircuitHalfOpen
+	}
+	if b.state == CircuitHalfOpen {
+		if b.probing {
+			return fmt.Errorf("%w; recovery probe in progress", ErrCircuitOpen)
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of a search that allow let
+// through.
+func (b *circuitBreaker) record(ctx context.Context, err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch breakerOutcome(ctx, err) {
+	case outcomeSuccess:
+		b.state = CircuitClosed
+		b.failures = 0
+		b.probing = false
+	case outcomeFailure:
+		b.failures++
+		if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+			b.state = CircuitOpen
+			b.openedAt = b.now()
+			b.failures = 0
+			b.probing = false
+		}
+	default:
+		b.probing = false // Let another search probe
+	}
+}
+
+// currentState returns the state, reporting an open circuit whose cooldown
+// has passed as half-open.
+func (b *circuitBreaker) currentState() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+type outcome int
+
+const (
+	outcomeIgnored outcome = iota
+	outcomeSuccess
+	outcomeFailure
+)
+
+// breakerOutcome classifies a search result for the circuit breaker.
+func breakerOutcome(ctx context.Context, err error) outcome {
+	if err == nil {
+		return outcomeSuccess
+	}
+	if ctx.Err() != nil {
+		return outcomeIgnored // The caller gave up; says nothing about the API
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode >= http.StatusInternalServerError {
+			return outcomeFailure
+		}
+		return outcomeSuccess // The API is up, it just rejected this request
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return outcomeFailure
+	}
+	return outcomeIgnored
+}
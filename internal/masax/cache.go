@@ -0,0 +1,135 @@
+package masax
+
+import (
+	"container/list"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithCache caches successful responses for up to ttl, keeping at most size
+// entries and evicting the least recently used. Requests are keyed on their
+// normalized query and all other parameters. Use WithoutCache to bypass the
+// cache for a single call.
+//
+// Expired responses that came with an ETag are kept and revalidated: the
+// next identical request sends If-None-Match, and a 304 Not Modified reply
+// returns the cached response and renews it for ttl. Responses without an
+// ETag are simply refetched.
+func WithCache(size int, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		if size > 0 && ttl > 0 {
+			c.cache = newResponseCache(size, ttl, c.now)
+		}
+	}
+}
+
+// WithoutCache makes a single call skip the cache, neither reading from nor
+// writing to it.
+func WithoutCache() SearchOption {
+	return func(o *searchOptions) {
+		o.bypassCache = true
+	}
+}
+
+// responseCache is a fixed-size LRU cache of search responses with expiry.
+type responseCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	now     func() time.Time
+	order   *list.List // Front is most recently used
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key     string
+	resp    *SearchResponse
+	expires time.Time
+}
+
+func newResponseCache(size int, ttl time.Duration, now func() time.Time) *responseCache {
+	return &responseCache{
+		size:    size,
+		ttl:     ttl,
+		now:     now,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns a copy of the cached response for key, if present and unexpired.
+func (rc *responseCache) get(key string) (*SearchResponse, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	elem, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if rc.now().After(entry.expires) {
+		if entry.resp.etag == "" { // Nothing to revalidate with
+			rc.order.Remove(elem)
+			delete(rc.entries, key)
+		}
+		return nil, false
+	}
+	rc.order.MoveToFront(elem)
+	return copyResponse(entry.resp), true
+}
+
+// stale returns a copy of the expired response for key if it can be
+// revalidated, i.e. it has an ETag.
+func (rc *responseCache) stale(key string) (*SearchResponse, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	elem, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if entry.resp.etag == "" {
+		return nil, false
+	}
+	return copyResponse(entry.resp), true
+}
+
+// put stores a copy of resp under key, evicting the least recently used entry
+// if the cache is full.
+func (rc *responseCache) put(key string, resp *SearchResponse) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry := &cacheEntry{key: key, resp: copyResponse(resp), expires: rc.now().Add(rc.ttl)}
+	if elem, ok := rc.entries[key]; ok {
+		elem.Value = entry
+		rc.order.MoveToFront(elem)
+		return
+	}
+	rc.entries[key] = rc.order.PushFront(entry)
+	if rc.order.Len() > rc.size {
+		oldest := rc.order.Back()
+		rc.order.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// copyResponse copies resp so callers can't mutate cached data.
+func copyResponse(resp *SearchResponse) *SearchResponse {
+	cp := *resp
+	cp.Items = slices.Clone(resp.Items) // Keeps an empty Items empty rather than nil
+	cp.Raw = slices.Clone(resp.Raw)
+	return &cp
+}
+
+// cacheKey identifies a request for caching. Queries differing only in case
+// or whitespace share a key.
+func cacheKey(searchReq SearchRequest) string {
+	searchReq.Query = strings.ToLower(strings.Join(strings.Fields(searchReq.Query), " "))
+	key, _ := json.Marshal(searchReq) // A plain struct of strings and ints can't fail to marshal
+	return string(key)
+}
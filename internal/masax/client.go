@@ -4,12 +4,19 @@ import (
 	"bytes" // Added for request body
 	"context"
 	"encoding/json" // Added for JSON marshaling/unmarshaling
+	"errors"
 	"fmt"
-	"io" // Added for reading response body
+	"log/slog"
 	"net/http"
 	"net/url" // Added for joining URL paths
+	"slices"
+	"sync"
 	"time"
 	// "os" // No longer needed directly here
+
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
 )
 
 // --- Request Structures ---
@@ -18,6 +25,11 @@ import (
 type SearchRequest struct {
 	Query      string `json:"query"`
 	MaxResults int    `json:"max_results,omitempty"`
+	NextToken  string `json:"next_token,omitempty"`
+	StartTime  string `json:"start_time,omitempty"` // RFC3339
+	EndTime    string `json:"end_time,omitempty"`   // RFC3339
+	SortBy     string `json:"sort_by,omitempty"`    // Server-side orders only; see SortOrder
+	Lang       string `json:"lang,omitempty"`       // ISO 639-1 code
 }
 
 // --- Response Structures ---
@@ -38,6 +50,13 @@ type SearchResult struct {
 	CreatedAt     time.Time     `json:"created_at"`
 	PublicMetrics PublicMetrics `json:"public_metrics"`
 	URL           string        `json:"url"`
+	Sentiment     *Sentiment    `json:"sentiment,omitempty"` // Set only when WithSentiment is used
+	Entities      *Entities     `json:"entities,omitempty"`  // Set only when WithEntities is used
+
+	// AuthorVerified reports whether the author has a verified (blue check)
+	// account, read from author_verified or author.verified. It is nil if the
+	// API didn't say, which the Masa X API currently doesn't.
+	AuthorVerified *bool `json:"author_verified,omitempty"`
 }
 
 // SearchMetadata contains pagination or summary info for the search response.
@@ -50,6 +69,30 @@ type SearchMetadata struct {
 type SearchResponse struct {
 	Items    []SearchResult `json:"items"`
 	Metadata SearchMetadata `json:"metadata"`
+	DryRun   *DryRunRequest `json:"dry_run,omitempty"` // Set only by WithDryRun
+
+	// Freshness, set by the client rather than the API. For results merged
+	// from several pages, FetchedAt is the oldest page's and FromCache is
+	// set if any page came from the cache.
+	FetchedAt time.Time `json:"-"` // When the API returned or last revalidated the response
+	FromCache bool      `json:"-"` // Served from the response cache without an API call
+
+	// Raw is the response body as the API sent it, before any client-side
+	// filtering, truncation or enrichment, set only with WithRawResponse.
+	// For results merged from several pages, it's the last page's, like
+	// Metadata. Empty responses have none.
+	Raw json.RawMessage `json:"-"`
+
+	etag string // The response's ETag, used to revalidate it once cached
+}
+
+// mergeFreshness folds the freshness of page into r, which merges several
+// pages; first marks the first page.
+func (r *SearchResponse) mergeFreshness(page *SearchResponse, first bool) {
+	if first || page.FetchedAt.Before(r.FetchedAt) {
+		r.FetchedAt = page.FetchedAt
+	}
+	r.FromCache = r.FromCache || page.FromCache
 }
 
 // ErrorDetail represents the structure within an API error response.
@@ -66,15 +109,79 @@ type ErrorResponse struct {
 // --- Client Implementation ---
 
 const (
-	defaultBaseURL = "https://data.dev.masalabs.ai/api/v1"
-	searchPath     = "/search/live/twitter"
+	defaultBaseURL    = "https://data.dev.masalabs.ai/api/v1"
+	defaultSearchPath = "/search/live/twitter"
+	defaultTimeout    = 15 * time.Second
+	defaultUserAgent  = "masax-mcp/0.1.0"
+
+	// DefaultMaxResultsLimit is the largest max_results accepted per request
+	// unless overridden with WithMaxResultsLimit.
+	DefaultMaxResultsLimit = 100
 )
 
 // Client manages communication with the Masa X API.
 type Client struct {
 	httpClient *http.Client
+	transport  http.RoundTripper // From WithTransport; nil keeps httpClient's
+	proxyURL   *url.URL          // From WithProxy; nil uses the transport's proxy settings
+	tls        tlsSettings       // From the TLS options
+	dial       dialSettings      // From the connection timeout options
 	apiBaseURL string
-	apiKey     string
+	apiVersion string // From WithAPIVersion; empty keeps the base URL's
+	searchPath string
+	searchVerb string // From WithSearchMethod: GET or POST
+	lookupPath string // From WithLookupPath; empty means GetByID is unsupported
+	userAgent  string
+	auth       AuthScheme
+	timeout    time.Duration // Per-attempt timeout; 0 means none
+
+	defaultDeadline time.Duration // Whole-call bound for contexts without a deadline; 0 means none
+
+	maxResultsLimit int
+	maxQueryLength  int // 0 means unlimited
+
+	maxResponseBytes int64
+
+	// Retry configuration; maxAttempts of 1 disables retries.
+	maxAttempts int
+	baseDelay   time.Duration
+	retryCounts retryCounters
+
+	retryMaxTotal time.Duration // Bound on all attempts and sleeps of a search; 0 means none
+	retryMaxDelay time.Duration // Cap on each backoff sleep
+
+	nonIdempotentSearch bool // Retry searches only when they can't have been processed
+
+	engagementWeights EngagementWeights // Used to rank results by engagement
+	synonyms          Synonyms          // For searches using WithQueryExpansion
+
+	strictContentType bool // Reject non-JSON success responses without parsing
+	strictValidation  bool // Reject search responses missing required fields
+	captureRaw        bool // Keep response bodies in SearchResponse.Raw
+	allowInsecure     bool // Permit a plain http base URL on a non-loopback host
+
+	inflight singleflight.Group      // Shares concurrent identical searches
+	sharedMu sync.Mutex              // Guards shared
+	shared   map[string]*sharedFetch // Waiters on each in-flight shared search
+
+	limiter              *rate.Limiter   // nil means unlimited
+	breaker              *circuitBreaker // nil disables the circuit breaker
+	logger               *slog.Logger    // nil disables logging
+	cache                *responseCache  // nil disables caching
+	filters              []FilterFunc
+	hooks                []SearchHook
+	requestInterceptors  []RequestInterceptor
+	responseInterceptors []ResponseInterceptor
+	tracer               trace.Tracer
+	clock                Clock
+	headers              []header // Custom headers, in application order
+
+	optionErr error // First invalid option, returned by NewClient
+
+	usage usageTracker // Quota from the latest rate-limit headers
+
+	keyMu  sync.RWMutex
+	apiKey string // Guarded by keyMu; see SetAPIKey
 }
 
 // NewClient creates a new Masa X API client.
@@ -83,16 +190,66 @@ func NewClient(apiKey string, options ...ClientOption) (*Client, error) {
 		return nil, fmt.Errorf("masa X API key is required")
 	}
 	c := &Client{
-		httpClient: &http.Client{Timeout: 15 * time.Second},
+		httpClient: &http.Client{Transport: newDefaultTransport()},
 		apiBaseURL: defaultBaseURL,
+		searchPath: defaultSearchPath,
+		searchVerb: http.MethodPost,
 		apiKey:     apiKey,
+		userAgent:  defaultUserAgent,
+		auth:       BearerAuth(),
+		timeout:    defaultTimeout,
+		tracer:     defaultTracer(),
+		clock:      realClock{},
+
+		maxResultsLimit: DefaultMaxResultsLimit,
+
+		maxResponseBytes: defaultMaxResponseBytes,
+
+		maxAttempts: 1,
+		baseDelay:   defaultRetryBaseDelay,
+
+		retryMaxDelay: maxRetryDelay,
+
+		engagementWeights: DefaultEngagementWeights,
 	}
 	for _, opt := range options {
 		opt(c)
 	}
+	if c.optionErr != nil {
+		return nil, c.optionErr
+	}
+	if err := c.applyAPIVersion(); err != nil {
+		return nil, err
+	}
+	if err := c.checkBaseURL(); err != nil {
+		return nil, err
+	}
+	if err := c.configureTransport(); err != nil {
+		return nil, err
+	}
 	return c, nil
 }
 
+// SetAPIKey replaces the API key used for subsequent requests, e.g. after the
+// key is rotated. It is safe to call while searches are in flight; requests
+// already sent keep the key they were built with.
+func (c *Client) SetAPIKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("masa X API key is required")
+	}
+	c.keyMu.Lock()
+	c.apiKey = key
+	c.keyMu.Unlock()
+	return nil
+}
+
+// currentAPIKey returns the API key to use for a new request.
+func (c *Client) currentAPIKey() string {
+	c.keyMu.RLock()
+	defer c.keyMu.RUnlock()
+	return c.apiKey
+}
+
 // ClientOption defines a functional option for configuring the Client.
 type ClientOption func(*Client)
 
@@ -105,7 +262,8 @@ func WithHTTPClient(client *http.Client) ClientOption {
 	}
 }
 
-// WithBaseURL allows overriding the default API base URL.
+// WithBaseURL allows overriding the default API base URL. NewClient rejects
+// plain http URLs, except on loopback hosts, unless WithAllowInsecure is set.
 func WithBaseURL(baseURL string) ClientOption {
 	return func(c *Client) {
 		if baseURL != "" {
@@ -114,64 +272,370 @@ func WithBaseURL(baseURL string) ClientOption {
 	}
 }
 
+// WithSearchPath allows overriding the search endpoint path, relative to the base URL.
+func WithSearchPath(path string) ClientOption {
+	return func(c *Client) {
+		if path != "" {
+			c.searchPath = path
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+// ("masax-mcp/0.1.0" by default). A User-Agent set with WithHeader takes
+// precedence.
+func WithUserAgent(ua string) ClientOption {
+	return func(c *Client) {
+		if ua != "" {
+			c.userAgent = ua
+		}
+	}
+}
+
+// WithMaxResultsLimit sets the largest max_results accepted per request.
+// Requests above it fail without calling the API.
+func WithMaxResultsLimit(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxResultsLimit = n
+		}
+	}
+}
+
+// WithTimeout sets the default timeout for each HTTP attempt (15s unless
+// overridden). A zero duration disables the timeout. A shorter deadline on the
+// caller's context always takes precedence.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d >= 0 {
+			c.timeout = d
+		}
+	}
+}
+
 // Search performs a search query against the Masa X API.
-func (c *Client) Search(ctx context.Context, query string, maxResults int) (*SearchResponse, error) {
-	// 1. Create SearchRequest and marshal to JSON
+// If filters or deduplication are configured and maxResults is set, Search may
+// fetch additional pages to return up to maxResults results that remain; if
+// one of those later pages fails, the results so far are returned along
+// with the error.
+func (c *Client) Search(ctx context.Context, query string, maxResults int, opts ...SearchOption) (*SearchResponse, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
 	searchReq := SearchRequest{
 		Query:      query,
 		MaxResults: maxResults,
 	}
-	reqBodyBytes, err := json.Marshal(searchReq)
+	searchOpts := c.newSearchOptions(opts)
+	if searchOpts.reducesResults() && maxResults > 0 && !searchOpts.dryRun { // A dry run describes the first page
+		return c.searchFiltered(ctx, searchReq, searchOpts)
+	}
+	return c.search(ctx, searchReq, searchOpts)
+}
+
+// search sends searchReq to the Masa X API, retrying transient failures if configured.
+func (c *Client) search(ctx context.Context, searchReq SearchRequest, opts searchOptions) (*SearchResponse, error) {
+	if err := opts.applyTo(&searchReq); err != nil {
+		return nil, err
+	}
+
+	searchResp, err := c.fetch(ctx, searchReq, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		return nil, err
 	}
+	if searchReq.MaxResults > 0 && len(searchResp.Items) > searchReq.MaxResults {
+		// The API returned more than asked for; callers never get more
+		searchResp.Items = searchResp.Items[:searchReq.MaxResults]
+	}
+	opts.postProcess(searchResp)
+	return searchResp, nil
+}
+
+// fetch returns the API response for searchReq, from the cache if possible,
+// or else from a request shared with concurrent identical searches, and
+// reports the outcome to any search hooks.
+func (c *Client) fetch(ctx context.Context, searchReq SearchRequest, opts searchOptions) (*SearchResponse, error) {
+	if opts.dryRun {
+		return c.dryRunResponse(ctx, searchReq)
+	}
+	useCache := c.cache != nil && !opts.bypassCache
+	if useCache {
+		if cached, ok := c.cache.get(cacheKey(searchReq)); ok {
+			c.logDebug(ctx, "masax cache hit", "query", searchReq.Query, "max_results", searchReq.MaxResults)
+			c.emit(ctx, SearchEvent{Query: searchReq.Query, StatusCode: http.StatusOK, Cached: true})
+			cached.FromCache = true
+			return cached, nil
+		}
+	}
+	return c.fetchShared(ctx, searchReq, opts, useCache)
+}
 
-	// 2. Construct URL and create request
+// fetchUncached sends searchReq to the API, revalidating an expired cached
+// response if there is one, and caches the result.
+func (c *Client) fetchUncached(ctx context.Context, searchReq SearchRequest, opts searchOptions, useCache bool) (*SearchResponse, error) {
+	var stale *SearchResponse // An expired cached response to revalidate
+	if useCache {
+		if resp, ok := c.cache.stale(cacheKey(searchReq)); ok {
+			stale = resp
+			opts.ifNoneMatch = resp.etag
+		}
+	}
+
+	start := c.now()
+	if err := c.breaker.allow(); err != nil {
+		c.emit(ctx, SearchEvent{Query: searchReq.Query, Err: err})
+		return nil, err
+	}
+	spanCtx, span := c.startSearchSpan(ctx, searchReq)
+	searchResp, stats, err := c.fetchRemote(spanCtx, searchReq, opts)
+	endSearchSpan(span, stats, err)
+	c.breaker.record(ctx, err)
+	c.emit(ctx, SearchEvent{
+		Query:      searchReq.Query,
+		StatusCode: stats.statusCode,
+		Retries:    stats.retries,
+		Duration:   c.since(start),
+		Err:        err,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if searchResp == nil { // 304 Not Modified: the stale response is current
+		c.logDebug(ctx, "masax cache revalidated", "query", searchReq.Query, "max_results", searchReq.MaxResults)
+		searchResp = stale
+		searchResp.FetchedAt = c.now()
+	}
+	if useCache {
+		c.cache.put(cacheKey(searchReq), searchResp)
+	}
+	return searchResp, nil
+}
+
+// fetchStats records what happened while fetching from the API.
+type fetchStats struct {
+	statusCode int // Status of the last attempt, 0 if no response was received
+	retries    int
+}
+
+// fetchRemote sends searchReq to the API, retrying transient failures if configured.
+func (c *Client) fetchRemote(ctx context.Context, searchReq SearchRequest, opts searchOptions) (*SearchResponse, fetchStats, error) {
+	var stats fetchStats
+
+	// 1. Build the request: a JSON body, or query parameters for GET
+	method, fullURL, reqBodyBytes, err := c.searchHTTPRequest(searchReq)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	c.logDebug(ctx, "masax search", "query", searchReq.Query, "max_results", searchReq.MaxResults, "url", fullURL)
+
+	// 2. Send request, retrying transient failures if configured
+	start := c.now()
+	for attempt := 0; ; attempt++ {
+		stats.retries = attempt
+		if err := ctx.Err(); err != nil {
+			return nil, stats, err // Don't start another attempt once the caller has given up
+		}
+		if err := c.waitForRateLimit(ctx); err != nil {
+			return nil, stats, err
+		}
+
+		timeout := opts.timeout
+		if left, ok := c.retryBudgetLeft(start); ok && (timeout <= 0 || left < timeout) {
+			timeout = max(left, time.Millisecond) // Keep a budget overrun from meaning no timeout
+		}
+		searchResp, statusCode, err := c.send(ctx, method, fullURL, reqBodyBytes, opts.ifNoneMatch, attempt, timeout)
+		stats.statusCode = statusCode
+		if err == nil {
+			return searchResp, stats, nil
+		}
+
+		var retryErr *retryableError
+		if !errors.As(err, &retryErr) {
+			return nil, stats, err
+		}
+		if ctx.Err() != nil {
+			return nil, stats, retryErr.err
+		}
+		if attempt+1 >= c.maxAttempts {
+			c.retryCounts.exhausted.Add(1)
+			return nil, stats, retryErr.err
+		}
+		if !c.safeToRetry(retryErr) {
+			return nil, stats, retryErr.err // May have been processed; see WithNonIdempotentSearch
+		}
+
+		delay := retryErr.retryAfter
+		if delay <= 0 {
+			delay = c.backoff(attempt)
+		}
+		if delay > c.retryMaxDelay {
+			c.retryCounts.exhausted.Add(1)
+			return nil, stats, retryErr.err // The server wants a longer wait than allowed
+		}
+		if deadline, ok := ctx.Deadline(); ok && deadline.Sub(c.now()) < delay {
+			c.retryCounts.exhausted.Add(1)
+			return nil, stats, retryErr.err // No point waiting past the caller's deadline
+		}
+		if left, ok := c.retryBudgetLeft(start); ok && left <= delay {
+			c.retryCounts.exhausted.Add(1)
+			return nil, stats, retryErr.err // The retry budget would run out while waiting
+		}
+		if err := c.sleep(ctx, delay); err != nil {
+			return nil, stats, err
+		}
+		c.retryCounts.retries.Add(1)
+	}
+}
+
+// searchURL joins the base URL and search path.
+func (c *Client) searchURL() (string, error) {
 	// Use url.JoinPath for safer path joining (requires Go 1.19+)
-	fullURL, err := url.JoinPath(c.apiBaseURL, searchPath)
+	fullURL, err := url.JoinPath(c.apiBaseURL, c.searchPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create search URL: %w", err)
+		return "", fmt.Errorf("failed to create search URL: %w", err)
 	}
+	return fullURL, nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, bytes.NewBuffer(reqBodyBytes))
+// send executes a single search attempt, returning the HTTP status code if a
+// response was received. Failures that may succeed on retry are wrapped in a
+// *retryableError. If ifNoneMatch is set it is sent as If-None-Match, and a
+// 304 Not Modified reply returns a nil response and no error.
+func (c *Client) send(ctx context.Context, method, fullURL string, reqBodyBytes []byte, ifNoneMatch string, attempt int, timeout time.Duration) (*SearchResponse, int, error) {
+	var reqHeader http.Header
+	if ifNoneMatch != "" {
+		reqHeader = http.Header{"If-None-Match": {ifNoneMatch}}
+	}
+	respBodyBytes, respHeader, statusCode, err := c.do(ctx, method, fullURL, reqBodyBytes, reqHeader, attempt, timeout)
+	if err != nil {
+		return nil, statusCode, err
+	}
+	if statusCode == http.StatusNotModified {
+		return nil, statusCode, nil
+	}
+
+	// Unmarshal successful response. A 204 No Content or an empty 200 means
+	// the query matched nothing.
+	searchResp := SearchResponse{FetchedAt: c.now()}
+	if statusCode == http.StatusNoContent || len(bytes.TrimSpace(respBodyBytes)) == 0 {
+		searchResp.Items = []SearchResult{}
+		return &searchResp, statusCode, nil
+	}
+	if err := c.decodeJSON(respHeader, respBodyBytes, &searchResp); err != nil {
+		return nil, statusCode, err
+	}
+	if c.strictValidation {
+		if err := validateSearchResponse(respBodyBytes, &searchResp); err != nil {
+			return nil, statusCode, err
+		}
+	}
+	if c.captureRaw {
+		searchResp.Raw = slices.Clone(respBodyBytes)
+	}
+	searchResp.etag = respHeader.Get("ETag")
+
+	return &searchResp, statusCode, nil
+}
+
+// newRequest builds the HTTP request for one attempt: it sets the usual
+// headers, adds reqHeader and runs the request interceptors.
+func (c *Client) newRequest(ctx context.Context, method, fullURL string, reqBodyBytes []byte, reqHeader http.Header) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, bytes.NewReader(reqBodyBytes))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
-	// 3. Add headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	// Add headers
+	c.setHeaders(req)
+	if reqBodyBytes == nil {
+		req.Header.Del("Content-Type") // Nothing to describe
+	}
+	for key, values := range reqHeader {
+		req.Header[key] = values
+	}
+	if err := c.interceptRequest(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// do executes a single HTTP attempt, adding reqHeader to the usual headers,
+// and returns the body and headers of a 2xx response, or of a 304 response
+// to a conditional request, with the status code if a response was
+// received. Failures that may succeed on retry are wrapped in a
+// *retryableError.
+func (c *Client) do(ctx context.Context, method, fullURL string, reqBodyBytes []byte, reqHeader http.Header, attempt int, timeout time.Duration) ([]byte, http.Header, int, error) {
+	// Bound this attempt only; ctx itself still governs the overall call so a
+	// timed-out attempt can be retried.
+	attemptCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
-	// 4. Send request
+	req, err := c.newRequest(attemptCtx, method, fullURL, reqBodyBytes, reqHeader)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	// Send request
+	c.logDebug(ctx, "masax request", "method", req.Method, "url", fullURL, "retry", attempt, "headers", c.redactHeaders(req.Header))
+	start := c.now()
 	httpResp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
+		c.logDebug(ctx, "masax request failed", "url", fullURL, "retry", attempt, "latency", c.since(start), "error", err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			// Cancelled by the caller, not a transient failure. Wrap ctx.Err()
+			// explicitly so errors.Is matches it whatever the transport returned.
+			return nil, nil, 0, fmt.Errorf("failed to execute HTTP request: %w (%w)", ctxErr, err)
+		}
+		unsent := isDialError(err)
+		err = fmt.Errorf("failed to execute HTTP request: %w", err)
+		return nil, nil, 0, &retryableError{err: err, unsent: unsent}
 	}
 	defer httpResp.Body.Close()
+	c.usage.record(httpResp.Header, c.now())
 
-	// 5. Read response body
-	respBodyBytes, err := io.ReadAll(httpResp.Body)
+	// Read response body. Reading to EOF also lets the connection be reused.
+	respBodyBytes, err := readLimited(httpResp.Body, c.maxResponseBytes)
+	if errors.Is(err, ErrResponseTooLarge) {
+		return nil, nil, httpResp.StatusCode, fmt.Errorf("failed to read response body: %w", err)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, httpResp.StatusCode, fmt.Errorf("failed to read response body: %w (%w)", ctxErr, err)
+		}
+		return nil, nil, httpResp.StatusCode, &retryableError{err: fmt.Errorf("failed to read response body: %w", err)}
 	}
-
-	// 6. Check status code and handle errors
-	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
-		var apiError ErrorResponse
-		if json.Unmarshal(respBodyBytes, &apiError) == nil && apiError.Error.Message != "" {
-			// Return structured API error
-			return nil, fmt.Errorf("masa X API error (HTTP %d - %s): %s", httpResp.StatusCode, apiError.Error.Code, apiError.Error.Message)
+	if respBodyBytes, err = decodeBody(httpResp.Header, respBodyBytes, c.maxResponseBytes); err != nil {
+		if errors.Is(err, ErrResponseTooLarge) {
+			return nil, nil, httpResp.StatusCode, fmt.Errorf("failed to decompress response body: %w", err)
 		}
-		// Return generic HTTP error if body parsing failed or error format unexpected
-		return nil, fmt.Errorf("masa X API request failed with HTTP status %d: %s", httpResp.StatusCode, string(respBodyBytes))
+		return nil, nil, httpResp.StatusCode, &retryableError{err: fmt.Errorf("failed to decompress response body: %w", err)}
+	}
+	if respBodyBytes, err = c.intercept(httpResp, respBodyBytes); err != nil {
+		return nil, nil, httpResp.StatusCode, err
 	}
+	c.logDebug(ctx, "masax response", "url", fullURL, "status", httpResp.StatusCode, "retry", attempt, "latency", c.since(start))
 
-	// 7. Unmarshal successful response
-	var searchResp SearchResponse
-	if err := json.Unmarshal(respBodyBytes, &searchResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal successful response body: %w", err)
+	if httpResp.StatusCode == http.StatusNotModified && req.Header.Get("If-None-Match") != "" {
+		return nil, httpResp.Header, httpResp.StatusCode, nil
+	}
+
+	// Check status code and handle errors
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		apiErr := newAPIError(httpResp.StatusCode, httpResp.Header, respBodyBytes)
+		if isRetryableStatus(httpResp.StatusCode) {
+			retryErr := &retryableError{err: apiErr}
+			if httpResp.StatusCode == http.StatusTooManyRequests {
+				retryErr.retryAfter = parseRetryAfter(httpResp.Header.Get("Retry-After"), c.now())
+				retryErr.unsent = true // Rejected before being processed
+			}
+			return nil, nil, httpResp.StatusCode, retryErr
+		}
+		return nil, nil, httpResp.StatusCode, apiErr
 	}
 
-	return &searchResp, nil
+	return respBodyBytes, httpResp.Header, httpResp.StatusCode, nil
 }
@@ -0,0 +1,215 @@
+package masax
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient starts an httptest server running handler and returns a
+// client for it. The server is closed when the test ends.
+func newTestClient(t testing.TB, handler http.HandlerFunc, opts ...ClientOption) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c, err := NewClient("test-key", append([]ClientOption{WithBaseURL(srv.URL)}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c
+}
+
+// writeJSON writes body as a JSON response.
+func writeJSON(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, body)
+}
+
+// waitFor polls cond until it holds, failing the test after a second.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// bodyTracker is a RoundTripper counting the response bodies it hands out
+// and how many of them were closed.
+type bodyTracker struct {
+	transport *http.Transport
+	opened    atomic.Int32
+	closed    atomic.Int32
+}
+
+func (b *bodyTracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := b.transport.RoundTrip(req)
+	if err == nil {
+		b.opened.Add(1)
+		resp.Body = &trackedBody{ReadCloser: resp.Body, closed: &b.closed}
+	}
+	return resp, err
+}
+
+type trackedBody struct {
+	io.ReadCloser
+	once   sync.Once
+	closed *atomic.Int32
+}
+
+func (b *trackedBody) Close() error {
+	b.once.Do(func() { b.closed.Add(1) })
+	return b.ReadCloser.Close()
+}
+
+func TestSearchDeadlineOnSlowServer(t *testing.T) {
+	tests := []struct {
+		name        string
+		sendHeaders bool // Stall mid-body rather than before the response
+	}{
+		{"before response", false},
+		{"mid body", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(io.Discard, r.Body) // Lets the server notice the client hanging up
+				if tt.sendHeaders {
+					w.Header().Set("Content-Type", "application/json")
+					io.WriteString(w, `{"items":[`)
+					w.(http.Flusher).Flush()
+				}
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			}))
+			defer srv.Close()
+			defer close(release)
+
+			tracker := &bodyTracker{transport: http.DefaultTransport.(*http.Transport).Clone()}
+			c, err := NewClient("test-key", WithBaseURL(srv.URL), WithTransport(tracker))
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			before := runtime.NumGoroutine()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			start := time.Now()
+			resp, err := c.Search(ctx, "slow", 10)
+			elapsed := time.Since(start)
+
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("Search error = %v, want context.DeadlineExceeded", err)
+			}
+			if resp != nil {
+				t.Errorf("Search response = %+v, want nil", resp)
+			}
+			if elapsed > 500*time.Millisecond {
+				t.Errorf("Search took %v after a 50ms deadline", elapsed)
+			}
+			waitFor(t, "response bodies to be closed", func() bool {
+				return tracker.closed.Load() == tracker.opened.Load()
+			})
+			if tt.sendHeaders && tracker.opened.Load() == 0 {
+				t.Error("no response body was received")
+			}
+			tracker.transport.CloseIdleConnections()
+			waitFor(t, "request goroutines to exit", func() bool {
+				return runtime.NumGoroutine() <= before
+			})
+		})
+	}
+}
+
+func TestSearchEmptyResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"204 No Content", http.StatusNoContent, ""},
+		{"empty 200", http.StatusOK, ""},
+		{"whitespace 200", http.StatusOK, " \n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			})
+			resp, err := c.Search(context.Background(), "nothing matches", 10)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if resp.Items == nil || len(resp.Items) != 0 {
+				t.Errorf("Items = %#v, want an empty, non-nil slice", resp.Items)
+			}
+		})
+	}
+}
+
+func TestGetByIDEmptyResponse(t *testing.T) {
+	for _, status := range []int{http.StatusNoContent, http.StatusOK} {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}, WithLookupPath("tweets"))
+		_, err := c.GetByID(context.Background(), "123")
+		if !errors.Is(err, ErrEmptyResponse) {
+			t.Errorf("HTTP %d: GetByID error = %v, want ErrEmptyResponse", status, err)
+		}
+	}
+}
+
+func TestSearchQueryEncoding(t *testing.T) {
+	queries := []struct {
+		query, want string
+	}{
+		{"AC/DC tour", "AC/DC tour"},
+		{"#bitcoin price", "#bitcoin price"},
+		{"  rate  cut   odds ", "rate cut odds"},
+		{"50% off & free/fast #deal?", "50% off & free/fast #deal?"},
+	}
+	for _, method := range []string{http.MethodPost, http.MethodGet} {
+		for _, tt := range queries {
+			t.Run(method+" "+tt.query, func(t *testing.T) {
+				var got string
+				c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+					if r.Method != method {
+						t.Errorf("method = %s, want %s", r.Method, method)
+					}
+					if method == http.MethodGet {
+						got = r.URL.Query().Get("query")
+					} else {
+						var body SearchRequest
+						if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+							t.Errorf("decoding request body: %v", err)
+						}
+						got = body.Query
+					}
+					writeJSON(w, `{"items":[]}`)
+				}, WithSearchMethod(method))
+				if _, err := c.Search(context.Background(), tt.query, 10); err != nil {
+					t.Fatalf("Search: %v", err)
+				}
+				if got != tt.want {
+					t.Errorf("server received query %q, want %q", got, tt.want)
+				}
+			})
+		}
+	}
+}
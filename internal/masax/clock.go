@@ -0,0 +1,42 @@
+package masax
+
+import "time"
+
+// Clock is the source of time used for retry delays, cache expiry, the
+// circuit breaker cooldown, Retry-After dates and latency measurements.
+// Substitute a fake with WithClock to test time-dependent behavior without
+// real sleeps. The rate limiter set by WithRateLimit always uses real time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After returns a channel that receives the current time once d has
+	// elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the default Clock, backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// WithClock sets the clock the client reads time from. By default it uses
+// the system clock.
+func WithClock(clock Clock) ClientOption {
+	return func(c *Client) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// now returns the current time according to the client's clock.
+func (c *Client) now() time.Time {
+	return c.clock.Now()
+}
+
+// since returns the time elapsed since t according to the client's clock.
+func (c *Client) since(t time.Time) time.Duration {
+	return c.now().Sub(t)
+}
@@ -0,0 +1,202 @@
+package masax
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock that only moves when told to. After advances it by
+// the requested duration and fires at once, recording the wait, so retry
+// sleeps take no real time.
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.waits = append(f.waits, d)
+	f.now = f.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- f.now
+	return ch
+}
+
+// Advance moves the clock forward by d.
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// Waits returns the durations passed to After so far.
+func (f *fakeClock) Waits() []time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]time.Duration(nil), f.waits...)
+}
+
+func TestCacheExpiryWithFakeClock(t *testing.T) {
+	clock := newFakeClock()
+	var requests atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		writeJSON(w, `{"items":[{"id":"1"}]}`)
+	}, WithClock(clock), WithCache(10, time.Minute))
+
+	search := func() *SearchResponse {
+		t.Helper()
+		resp, err := c.Search(context.Background(), "cached", 10)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		return resp
+	}
+
+	fetchedAt := clock.Now()
+	if resp := search(); !resp.FetchedAt.Equal(fetchedAt) {
+		t.Errorf("FetchedAt = %v, want the fake clock's %v", resp.FetchedAt, fetchedAt)
+	}
+	clock.Advance(time.Minute - time.Second)
+	resp := search()
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("server got %d requests before the TTL passed, want 1", n)
+	}
+	if !resp.FetchedAt.Equal(fetchedAt) {
+		t.Errorf("cached FetchedAt = %v, want the original %v", resp.FetchedAt, fetchedAt)
+	}
+
+	clock.Advance(2 * time.Second)
+	if resp := search(); !resp.FetchedAt.Equal(clock.Now()) {
+		t.Errorf("refetched FetchedAt = %v, want %v", resp.FetchedAt, clock.Now())
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server got %d requests after the TTL passed, want 2", n)
+	}
+}
+
+// flakyHandler fails the first failures requests with status, setting the
+// given headers, and then succeeds.
+func flakyHandler(failures int32, status int, header map[string]string) (http.HandlerFunc, *atomic.Int32) {
+	var requests atomic.Int32
+	return func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			for k, v := range header {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		writeJSON(w, `{"items":[]}`)
+	}, &requests
+}
+
+func TestRetryBackoffWithFakeClock(t *testing.T) {
+	clock := newFakeClock()
+	handler, requests := flakyHandler(3, http.StatusServiceUnavailable, nil)
+	c := newTestClient(t, handler, WithClock(clock), WithRetry(4, time.Second))
+
+	start := time.Now()
+	if _, err := c.Search(context.Background(), "flaky", 10); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Search took %v of real time, want no real sleeps", elapsed)
+	}
+	if n := requests.Load(); n != 4 {
+		t.Errorf("server got %d requests, want 4", n)
+	}
+	waits := clock.Waits()
+	if len(waits) != 3 {
+		t.Fatalf("waits = %v, want 3", waits)
+	}
+	for i, d := range waits {
+		// baseDelay*2^i with the upper half randomized
+		ceiling := time.Second << i
+		if d < ceiling/2 || d > ceiling {
+			t.Errorf("wait %d = %v, want within [%v, %v]", i+1, d, ceiling/2, ceiling)
+		}
+	}
+	if stats := c.RetryStats(); stats.Retries != 3 || stats.Exhausted != 0 {
+		t.Errorf("RetryStats = %+v, want 3 retries", stats)
+	}
+}
+
+func TestBackoffCappedForLargeAttempts(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxDelay time.Duration // 0 keeps the default
+		want     time.Duration // The cap
+	}{
+		{"default cap", 0, 30 * time.Second},
+		{"huge cap", math.MaxInt64, math.MaxInt64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient("test-key", WithRetry(100, 500*time.Millisecond), WithRetryBudget(0, tt.maxDelay))
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			for _, attempt := range []int{34, 35, 63, 64, 100} {
+				if d := c.backoff(attempt); d < tt.want/2 || d > tt.want {
+					t.Errorf("backoff(%d) = %v, want within [%v, %v]", attempt, d, tt.want/2, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryAfterDateWithFakeClock(t *testing.T) {
+	clock := newFakeClock()
+	retryAt := clock.Now().Add(20 * time.Second).Format(http.TimeFormat)
+	handler, requests := flakyHandler(1, http.StatusTooManyRequests, map[string]string{"Retry-After": retryAt})
+	c := newTestClient(t, handler, WithClock(clock), WithRetry(2, time.Second))
+
+	if _, err := c.Search(context.Background(), "limited", 10); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+	if waits := clock.Waits(); len(waits) != 1 || waits[0] != 20*time.Second {
+		t.Errorf("waits = %v, want the 20s until the Retry-After date on the fake clock", waits)
+	}
+}
+
+func TestRetryBudgetWithFakeClock(t *testing.T) {
+	clock := newFakeClock()
+	handler, requests := flakyHandler(10, http.StatusServiceUnavailable, nil)
+	// The first backoff takes 4-8s of the 10s budget, leaving less than the
+	// second's 8-16s.
+	c := newTestClient(t, handler, WithClock(clock), WithRetry(5, 8*time.Second), WithRetryBudget(10*time.Second, 0))
+
+	if _, err := c.Search(context.Background(), "flaky", 10); err == nil {
+		t.Fatal("Search succeeded, want the budget to run out")
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server got %d requests, want 2", n)
+	}
+	if waits := clock.Waits(); len(waits) != 1 {
+		t.Errorf("waits = %v, want one", waits)
+	}
+	if stats := c.RetryStats(); stats.Retries != 1 || stats.Exhausted != 1 {
+		t.Errorf("RetryStats = %+v, want 1 retry and 1 exhausted search", stats)
+	}
+}
@@ -0,0 +1,28 @@
+package masax
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+)
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// decodeBody returns the decompressed body when the response is
+// gzip-encoded. A body labelled gzip that isn't, as some proxies send, is
+// returned unchanged. net/http already decompresses transparently when it
+// added Accept-Encoding itself, but not when the header is set explicitly,
+// as setHeaders does. The decompressed body is limited to maxBytes.
+func decodeBody(header http.Header, body []byte, maxBytes int64) ([]byte, error) {
+	if !strings.EqualFold(strings.TrimSpace(header.Get("Content-Encoding")), "gzip") || !bytes.HasPrefix(body, gzipMagic) {
+		return body, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return readLimited(zr, maxBytes)
+}
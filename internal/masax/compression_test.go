@@ -0,0 +1,102 @@
+package masax
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gzipped returns s gzip-compressed.
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatalf("compressing: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("compressing: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestGzipResponses(t *testing.T) {
+	// Padded so it compresses well below its own size
+	page := `{"items":[{"id":"1","text":"compressed"},{"id":"2","text":"twice"}],"pad":"` + strings.Repeat("x", 1024) + `"}`
+	// Compresses to a few hundred bytes but inflates to 64 KiB
+	bomb := `{"items":[{"id":"1","text":"` + strings.Repeat("a", 64<<10) + `"}]}`
+
+	tests := []struct {
+		name      string
+		encoding  string // Content-Encoding sent by the server
+		body      []byte
+		opts      []ClientOption
+		wantItems int
+		wantErr   error
+	}{
+		{name: "gzip", encoding: "gzip", body: gzipped(t, page), wantItems: 2},
+		{name: "gzip mixed case", encoding: " GZip ", body: gzipped(t, page), wantItems: 2},
+		{name: "plain", body: []byte(page), wantItems: 2},
+		{name: "labelled gzip but plain", encoding: "gzip", body: []byte(page), wantItems: 2},
+		{
+			name:      "gzip at limit",
+			encoding:  "gzip",
+			body:      gzipped(t, page),
+			opts:      []ClientOption{WithMaxResponseBytes(int64(len(page)))},
+			wantItems: 2,
+		},
+		{
+			name:     "decompressed over limit",
+			encoding: "gzip",
+			body:     gzipped(t, bomb),
+			opts:     []ClientOption{WithMaxResponseBytes(4 << 10)},
+			wantErr:  ErrResponseTooLarge,
+		},
+		{
+			name:    "plain over limit",
+			body:    []byte(bomb),
+			opts:    []ClientOption{WithMaxResponseBytes(4 << 10)},
+			wantErr: ErrResponseTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			opts := append([]ClientOption{WithRetry(3, time.Millisecond)}, tt.opts...)
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				if got := r.Header.Get("Accept-Encoding"); got != "gzip" {
+					t.Errorf("Accept-Encoding = %q, want gzip", got)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				w.Write(tt.body)
+			}, opts...)
+
+			resp, err := c.Search(context.Background(), "compression", 10)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Search error = %v, want %v", err, tt.wantErr)
+				}
+				if n := requests.Load(); n != 1 {
+					t.Errorf("server got %d requests, want 1: oversized responses aren't retried", n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if len(resp.Items) != tt.wantItems || resp.Items[0].Text != "compressed" {
+				t.Errorf("Items = %+v, want the %d decoded results", resp.Items, tt.wantItems)
+			}
+		})
+	}
+}
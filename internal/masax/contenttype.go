@@ -0,0 +1,84 @@
+package masax
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnexpectedContentType is returned, wrapped, when a successful response
+// isn't JSON, e.g. an HTML page from a misconfigured gateway.
+var ErrUnexpectedContentType = errors.New("masa X API: unexpected content type")
+
+// maxBodyExcerpt bounds how much of a non-JSON body is quoted in errors.
+const maxBodyExcerpt = 512
+
+// WithStrictContentType rejects successful responses whose Content-Type
+// isn't JSON without trying to parse them. By default the body is parsed as
+// JSON whatever its Content-Type, since some proxies label JSON as
+// text/plain, and the Content-Type is only used to explain a body that
+// fails to parse.
+func WithStrictContentType() ClientOption {
+	return func(c *Client) {
+		c.strictContentType = true
+	}
+}
+
+// decodeJSON unmarshals a successful response body into v. A body that
+// isn't JSON is reported with its Content-Type and an excerpt, wrapping
+// ErrUnexpectedContentType.
+func (c *Client) decodeJSON(header http.Header, body []byte, v interface{}) error {
+	contentType := header.Get("Content-Type")
+	if c.strictContentType && !isJSONContentType(contentType) {
+		return unexpectedContentType(contentType, body)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		if !isJSONContentType(contentType) && !looksLikeJSON(body) {
+			return unexpectedContentType(contentType, body)
+		}
+		return fmt.Errorf("failed to unmarshal successful response body: %w", err)
+	}
+	return nil
+}
+
+func unexpectedContentType(contentType string, body []byte) error {
+	if contentType == "" {
+		contentType = "no Content-Type"
+	}
+	return fmt.Errorf("%w: got %s instead of JSON: %q", ErrUnexpectedContentType, contentType, bodyExcerpt(body))
+}
+
+// isJSONContentType reports whether a Content-Type names JSON, such as
+// application/json or application/problem+json.
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// looksLikeJSON reports whether body starts like a JSON object or array.
+func looksLikeJSON(body []byte) bool {
+	body = bytes.TrimSpace(body)
+	return len(body) > 0 && (body[0] == '{' || body[0] == '[')
+}
+
+// bodyExcerpt returns the start of body for error messages, cut at a rune
+// boundary.
+func bodyExcerpt(body []byte) string {
+	body = bytes.TrimSpace(body)
+	if len(body) <= maxBodyExcerpt {
+		return string(body)
+	}
+	cut := maxBodyExcerpt
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return string(body[:cut]) + "..."
+}
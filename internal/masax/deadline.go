@@ -0,0 +1,44 @@
+package masax
+
+import (
+	"context"
+	"time"
+)
+
+// defaultDeadlineKey marks a context whose deadline is the client's default
+// deadline rather than one the caller set.
+type defaultDeadlineKey struct{}
+
+// WithDefaultDeadline bounds each call whose context has no deadline to d,
+// covering rate-limit waits, retries and backoff as well as the requests
+// themselves. Unlike WithTimeout, which bounds each attempt, it caps the
+// worst-case latency of the whole call. Contexts that already have a
+// deadline, shorter or longer, keep it. SearchAll is bounded as a whole;
+// SearchBatch and SearchIterator bound each search they make. By default
+// there is no fallback deadline.
+func WithDefaultDeadline(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d > 0 {
+			c.defaultDeadline = d
+		}
+	}
+}
+
+// callContext prepares the context of a public call: it applies the default
+// deadline and ensures the context carries a request ID.
+func (c *Client) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return c.withDefaultDeadline(ensureRequestID(ctx))
+}
+
+// withDefaultDeadline applies the default deadline to ctx if it has none,
+// marking it as the default so the call can still be shared.
+func (c *Client) withDefaultDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.defaultDeadline <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.defaultDeadline)
+	return context.WithValue(ctx, defaultDeadlineKey{}, true), cancel
+}
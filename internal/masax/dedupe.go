@@ -0,0 +1,55 @@
+package masax
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	urlPattern     = regexp.MustCompile(`https?://\S+`)
+	mentionPattern = regexp.MustCompile(`@\w+`)
+)
+
+// Normalizer maps post text to a key; posts with equal keys are duplicates.
+type Normalizer func(text string) string
+
+// NormalizeText is the default Normalizer. It lower-cases text, strips URLs
+// and @mentions, and collapses whitespace, so retweet chains and copy-paste
+// posts that differ only in links or mentions compare equal.
+func NormalizeText(text string) string {
+	text = strings.ToLower(text)
+	text = urlPattern.ReplaceAllString(text, " ")
+	text = mentionPattern.ReplaceAllString(text, " ")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// WithDedupe removes results whose normalized text duplicates another
+// result's, keeping the copy with the highest weighted engagement score (see
+// WithEngagementWeights). A nil normalize uses NormalizeText.
+func WithDedupe(normalize Normalizer) SearchOption {
+	return func(o *searchOptions) {
+		if normalize == nil {
+			normalize = NormalizeText
+		}
+		o.dedupe = normalize
+	}
+}
+
+// dedupeItems keeps one result per normalized text. The kept result takes
+// the position of the first copy seen so the API's ordering is preserved.
+func dedupeItems(items []SearchResult, normalize Normalizer, w EngagementWeights) []SearchResult {
+	index := make(map[string]int, len(items)) // Normalized text -> position in kept
+	kept := items[:0]
+	for _, item := range items {
+		key := normalize(item.Text)
+		if i, ok := index[key]; ok {
+			if w.Score(item.PublicMetrics) > w.Score(kept[i].PublicMetrics) {
+				kept[i] = item
+			}
+			continue
+		}
+		index[key] = len(kept)
+		kept = append(kept, item)
+	}
+	return kept
+}
@@ -0,0 +1,100 @@
+package masax
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Hello World", "hello world"},
+		{"  spaced \t out\n text ", "spaced out text"},
+		{"RT @alice: BTC to 100k https://t.co/abc123", "rt : btc to 100k"},
+		{"btc to 100k http://example.com/x?y=1 @bob @carol_1", "btc to 100k"},
+		{"", ""},
+		{"@only https://t.co/x", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeText(tt.in); got != tt.want {
+			t.Errorf("NormalizeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDedupeItems(t *testing.T) {
+	post := func(id, text string, likes, retweets int) SearchResult {
+		return SearchResult{ID: id, Text: text, PublicMetrics: PublicMetrics{LikeCount: likes, RetweetCount: retweets}}
+	}
+	items := []SearchResult{
+		post("1", "BTC to 100k https://t.co/a", 1, 0),
+		post("2", "something else", 0, 0),
+		post("3", "btc to   100K @alice", 5, 0),
+		post("4", "BTC to 100k https://t.co/b", 5, 0), // Ties with 3; the earlier copy stays
+		post("5", "Something Else", 0, 3),
+	}
+
+	tests := []struct {
+		name      string
+		normalize Normalizer
+		weights   EngagementWeights
+		want      string
+	}{
+		// The kept copy takes the position of the first one seen
+		{"default", NormalizeText, DefaultEngagementWeights, "3,5"},
+		{"weights change the winner", NormalizeText, EngagementWeights{Likes: 1}, "3,2"},
+		{"custom normalizer", strings.TrimSpace, DefaultEngagementWeights, "1,2,3,4,5"},
+		{"custom normalizer merging all", func(string) string { return "" }, DefaultEngagementWeights, "3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dedupeItems(append([]SearchResult(nil), items...), tt.normalize, tt.weights)
+			if ids := itemIDs(got); ids != tt.want {
+				t.Errorf("kept %s, want %s", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchDedupe(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, `{"items":[
+			{"id":"1","text":"Rates are up https://t.co/a","public_metrics":{"like_count":2}},
+			{"id":"2","text":"RT @bob: rates are UP","public_metrics":{"like_count":9}},
+			{"id":"3","text":"rates are up @carol","public_metrics":{"like_count":4}},
+			{"id":"4","text":"bonds slip"}
+		]}`)
+	})
+
+	resp, err := c.Search(context.Background(), "rates", 10)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if got := itemIDs(resp.Items); got != "1,2,3,4" {
+		t.Errorf("without dedupe got %s, want every post", got)
+	}
+
+	resp, err = c.Search(context.Background(), "rates", 10, WithDedupe(nil))
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	// "rt : rates are up" differs from "rates are up", so the retweet stays
+	if got := itemIDs(resp.Items); got != "3,2,4" {
+		t.Errorf("with dedupe got %s, want 3,2,4", got)
+	}
+
+	// Also folds "RT @user: text" into "text"
+	stripRT := func(text string) string {
+		return strings.TrimLeft(NormalizeText(strings.TrimPrefix(text, "RT ")), ": ")
+	}
+	resp, err = c.Search(context.Background(), "rates", 10, WithDedupe(stripRT))
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if got := itemIDs(resp.Items); got != "2,4" {
+		t.Errorf("with a custom normalizer got %s, want 2,4", got)
+	}
+}
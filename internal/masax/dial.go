@@ -0,0 +1,60 @@
+package masax
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// defaultDialKeepAlive matches the keep-alive period of http.DefaultTransport,
+// which WithConnectTimeout replaces the dialer of.
+const defaultDialKeepAlive = 30 * time.Second
+
+// WithConnectTimeout limits how long establishing the TCP connection to the
+// API may take (30s in http.DefaultTransport). Unlike WithTimeout, which
+// covers an entire attempt including reading the body, it lets an
+// unreachable host fail fast while a slow but progressing transfer keeps
+// its longer budget. A zero or negative duration keeps the transport's.
+// Like WithProxy, it requires the Transport to be an *http.Transport;
+// NewClient returns an error if it isn't.
+func WithConnectTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d > 0 {
+			c.dial.connect = d
+		}
+	}
+}
+
+// WithTLSHandshakeTimeout limits how long the TLS handshake with the API may
+// take once connected (10s in http.DefaultTransport). A zero or negative
+// duration keeps the transport's. Like WithProxy, it requires the Transport
+// to be an *http.Transport; NewClient returns an error if it isn't.
+func WithTLSHandshakeTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d > 0 {
+			c.dial.tlsHandshake = d
+		}
+	}
+}
+
+// dialSettings holds the connection timeout options.
+type dialSettings struct {
+	connect      time.Duration // From WithConnectTimeout; 0 keeps the transport's
+	tlsHandshake time.Duration // From WithTLSHandshakeTimeout; 0 keeps the transport's
+}
+
+// configured reports whether any connection timeout option was set.
+func (s dialSettings) configured() bool {
+	return s.connect != 0 || s.tlsHandshake != 0
+}
+
+// apply sets the configured timeouts on t.
+func (s dialSettings) apply(t *http.Transport) {
+	if s.connect != 0 {
+		dialer := &net.Dialer{Timeout: s.connect, KeepAlive: defaultDialKeepAlive}
+		t.DialContext = dialer.DialContext
+	}
+	if s.tlsHandshake != 0 {
+		t.TLSHandshakeTimeout = s.tlsHandshake
+	}
+}
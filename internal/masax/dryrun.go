@@ -0,0 +1,49 @@
+package masax
+
+import (
+	"context"
+	"net/http"
+)
+
+// DryRunRequest describes the request a dry-run search would have sent.
+type DryRunRequest struct {
+	Method  string        `json:"method"`
+	URL     string        `json:"url"`
+	Headers http.Header   `json:"headers"` // Credentials are redacted
+	Body    SearchRequest `json:"body"`    // For GET, sent as the URL's query parameters instead
+}
+
+// WithDryRun makes Search return a response describing the request it would
+// have sent, in SearchResponse.DryRun, instead of calling the API. Options
+// are still validated, and the response has no items. Searches that may
+// fetch several pages describe only the first request. The request is built
+// as for a real send, so it carries the request ID and any changes made by
+// request interceptors, which run as usual. Dry runs bypass the cache, rate
+// limiter and circuit breaker and aren't reported to search hooks.
+func WithDryRun() SearchOption {
+	return func(o *searchOptions) {
+		o.dryRun = true
+	}
+}
+
+// dryRunResponse synthesizes the response for a dry-run search, building
+// the request as send would but stopping short of sending it.
+func (c *Client) dryRunResponse(ctx context.Context, searchReq SearchRequest) (*SearchResponse, error) {
+	method, fullURL, body, err := c.searchHTTPRequest(searchReq)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.newRequest(ctx, method, fullURL, body, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SearchResponse{
+		Items: []SearchResult{},
+		DryRun: &DryRunRequest{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: c.redactHeaders(req.Header),
+			Body:    searchReq,
+		},
+	}, nil
+}
@@ -0,0 +1,137 @@
+package masax
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// EngagementStats summarizes the engagement of a set of results.
+type EngagementStats struct {
+	PostCount     int           `json:"post_count"`
+	PublicMetrics PublicMetrics `json:"public_metrics"` // Totals
+	Engagement    int           `json:"engagement"`     // Total EngagementScore
+
+	// Per-post averages; zero if there are no posts
+	AvgLikes      float64 `json:"avg_likes"`
+	AvgRetweets   float64 `json:"avg_retweets"`
+	AvgEngagement float64 `json:"avg_engagement"`
+}
+
+// SummarizeEngagement totals the public metrics of items and averages them
+// per post.
+func SummarizeEngagement(items []SearchResult) EngagementStats {
+	var stats EngagementStats
+	for _, item := range items {
+		stats.PostCount++
+		stats.PublicMetrics.RetweetCount += item.PublicMetrics.RetweetCount
+		stats.PublicMetrics.ReplyCount += item.PublicMetrics.ReplyCount
+		stats.PublicMetrics.LikeCount += item.PublicMetrics.LikeCount
+		stats.PublicMetrics.QuoteCount += item.PublicMetrics.QuoteCount
+		stats.Engagement += EngagementScore(item.PublicMetrics)
+	}
+	if stats.PostCount > 0 {
+		n := float64(stats.PostCount)
+		stats.AvgLikes = float64(stats.PublicMetrics.LikeCount) / n
+		stats.AvgRetweets = float64(stats.PublicMetrics.RetweetCount) / n
+		stats.AvgEngagement = float64(stats.Engagement) / n
+	}
+	return stats
+}
+
+// EngagementWeights sets how much each public metric counts towards a
+// weighted engagement score. The zero value counts nothing; use
+// DefaultEngagementWeights for equal weighting.
+type EngagementWeights struct {
+	Likes    float64 `json:"likes"`
+	Retweets float64 `json:"retweets"`
+	Replies  float64 `json:"replies"`
+	Quotes   float64 `json:"quotes"`
+}
+
+// DefaultEngagementWeights counts every metric once, matching
+// EngagementScore.
+var DefaultEngagementWeights = EngagementWeights{Likes: 1, Retweets: 1, Replies: 1, Quotes: 1}
+
+// Score returns the weighted sum of m's metrics.
+func (w EngagementWeights) Score(m PublicMetrics) float64 {
+	return w.Likes*float64(m.LikeCount) + w.Retweets*float64(m.RetweetCount) +
+		w.Replies*float64(m.ReplyCount) + w.Quotes*float64(m.QuoteCount)
+}
+
+// Validate reports a negative, infinite or NaN weight.
+func (w EngagementWeights) Validate() error {
+	for _, weight := range []struct {
+		name  string
+		value float64
+	}{{"likes", w.Likes}, {"retweets", w.Retweets}, {"replies", w.Replies}, {"quotes", w.Quotes}} {
+		if !(weight.value >= 0) || math.IsInf(weight.value, 0) {
+			return fmt.Errorf("invalid %s weight %v: must be a non-negative number", weight.name, weight.value)
+		}
+	}
+	return nil
+}
+
+// ParseEngagementWeights parses weights written as comma-separated
+// metric=weight pairs, e.g. "likes=1,retweets=2,replies=1.5". Metrics not
+// given keep their default weight of 1, and an empty string yields
+// DefaultEngagementWeights.
+func ParseEngagementWeights(s string) (EngagementWeights, error) {
+	w := DefaultEngagementWeights
+	if strings.TrimSpace(s) == "" {
+		return w, nil
+	}
+	fields := map[string]*float64{"likes": &w.Likes, "retweets": &w.Retweets, "replies": &w.Replies, "quotes": &w.Quotes}
+	for _, pair := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		field, known := fields[strings.TrimSpace(name)]
+		if !ok || !known {
+			return EngagementWeights{}, fmt.Errorf("invalid engagement weight %q: expected likes, retweets, replies or quotes=<weight>", pair)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return EngagementWeights{}, fmt.Errorf("invalid engagement weight %q: %w", pair, err)
+		}
+		*field = weight
+	}
+	return w, w.Validate()
+}
+
+// WithEngagementWeights sets how the client weighs public metrics wherever
+// it ranks results by engagement: the engagement sort order and the choice
+// of which copy WithDedupe keeps. Weights default to
+// DefaultEngagementWeights. Aggregates such as EngagementStats and
+// AuthorStats still report plain counts. NewClient returns an error for
+// negative or non-finite weights.
+func WithEngagementWeights(w EngagementWeights) ClientOption {
+	return func(c *Client) {
+		if err := w.Validate(); err != nil {
+			c.setOptionErr(err)
+			return
+		}
+		c.engagementWeights = w
+	}
+}
+
+// EngagementWeights returns the weights set with WithEngagementWeights.
+func (c *Client) EngagementWeights() EngagementWeights {
+	return c.engagementWeights
+}
+
+// TopResult returns the item with the highest weighted engagement score,
+// breaking ties by descending post ID as the engagement sort does, and false
+// if there are no items.
+func TopResult(items []SearchResult, w EngagementWeights) (SearchResult, bool) {
+	if len(items) == 0 {
+		return SearchResult{}, false
+	}
+	best, bestScore := items[0], w.Score(items[0].PublicMetrics)
+	for _, item := range items[1:] {
+		score := w.Score(item.PublicMetrics)
+		if score > bestScore || score == bestScore && compareIDs(item.ID, best.ID) > 0 {
+			best, bestScore = item, score
+		}
+	}
+	return best, true
+}
@@ -0,0 +1,154 @@
+package masax
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// A hashtag must start the text or follow a character that can't be part
+	// of a word, so "abc#def" isn't one, and can't be all digits. Full-width
+	// signs count, as on X.
+	hashtagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&/])[#＃]([\p{L}\p{N}_]*[\p{L}_][\p{L}\p{N}_]*)`)
+	// Mentions follow the same rule, which also excludes email addresses.
+	// Handles longer than maxHandleLength are discarded after matching.
+	entityMentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_])[@＠]([A-Za-z0-9_]+)`)
+	entityURLPattern     = regexp.MustCompile(`https?://\S+`)
+)
+
+// maxHandleLength is the longest X username.
+const maxHandleLength = 15
+
+// urlTrailingPunctuation is trimmed from URLs that end a sentence or sit in
+// parentheses or quotes.
+const urlTrailingPunctuation = `.,;:!?)]}"'`
+
+// Entities are the hashtags, mentions and URLs found in a post's text.
+// Hashtags and mentions are lower-cased and given without their # or @.
+type Entities struct {
+	Hashtags []string `json:"hashtags,omitempty"`
+	Mentions []string `json:"mentions,omitempty"`
+	URLs     []string `json:"urls,omitempty"`
+}
+
+// ExtractEntities parses hashtags, mentions and URLs from text. URLs are
+// removed before looking for hashtags and mentions so fragments like
+// "/page#section" aren't mistaken for them.
+func ExtractEntities(text string) Entities {
+	var e Entities
+	for _, u := range entityURLPattern.FindAllString(text, -1) {
+		if u = strings.TrimRight(u, urlTrailingPunctuation); u != "" {
+			e.URLs = append(e.URLs, u)
+		}
+	}
+	text = entityURLPattern.ReplaceAllString(text, " ")
+	for _, m := range hashtagPattern.FindAllStringSubmatch(text, -1) {
+		e.Hashtags = append(e.Hashtags, strings.ToLower(m[1]))
+	}
+	for _, m := range entityMentionPattern.FindAllStringSubmatch(text, -1) {
+		if len(m[1]) <= maxHandleLength {
+			e.Mentions = append(e.Mentions, strings.ToLower(m[1]))
+		}
+	}
+	return e
+}
+
+// WithEntities parses each result's text with ExtractEntities and sets its
+// Entities field.
+func WithEntities() SearchOption {
+	return func(o *searchOptions) {
+		o.entities = true
+	}
+}
+
+// extractEntities sets Entities on items when WithEntities is used.
+func (o searchOptions) extractEntities(items []SearchResult) {
+	if !o.entities {
+		return
+	}
+	for i := range items {
+		e := ExtractEntities(items[i].Text)
+		items[i].Entities = &e
+	}
+}
+
+// EntityCount is how often an entity appeared across a set of results, and
+// the total EngagementScore of the results it appeared in.
+type EntityCount struct {
+	Value      string `json:"value"`
+	Count      int    `json:"count"`
+	Engagement int    `json:"engagement"`
+}
+
+// Trends aggregates the entities found across a set of results.
+type Trends struct {
+	Hashtags []EntityCount `json:"hashtags"`
+	Mentions []EntityCount `json:"mentions"`
+	URLs     []EntityCount `json:"urls"`
+}
+
+// AggregateTrends counts the entities in items and returns the top n of each
+// kind (all of them if n <= 0), ordered by count, then engagement, then
+// value. An entity repeated within one post is counted once for that post.
+func AggregateTrends(items []SearchResult, n int) Trends {
+	hashtags := newEntityCounter()
+	mentions := newEntityCounter()
+	urls := newEntityCounter()
+	for _, item := range items {
+		e := ExtractEntities(item.Text)
+		score := EngagementScore(item.PublicMetrics)
+		hashtags.add(e.Hashtags, score)
+		mentions.add(e.Mentions, score)
+		urls.add(e.URLs, score)
+	}
+	return Trends{
+		Hashtags: hashtags.top(n),
+		Mentions: mentions.top(n),
+		URLs:     urls.top(n),
+	}
+}
+
+type entityCounter map[string]*EntityCount
+
+func newEntityCounter() entityCounter {
+	return make(entityCounter)
+}
+
+// add counts each distinct value once, crediting it with score.
+func (ec entityCounter) add(values []string, score int) {
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		c, ok := ec[v]
+		if !ok {
+			c = &EntityCount{Value: v}
+			ec[v] = c
+		}
+		c.Count++
+		c.Engagement += score
+	}
+}
+
+func (ec entityCounter) top(n int) []EntityCount {
+	counts := make([]EntityCount, 0, len(ec))
+	for _, c := range ec {
+		counts = append(counts, *c)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		if counts[i].Engagement != counts[j].Engagement {
+			return counts[i].Engagement > counts[j].Engagement
+		}
+		return counts[i].Value < counts[j].Value
+	})
+	if n > 0 && len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
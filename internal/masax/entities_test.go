@@ -0,0 +1,144 @@
+package masax
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestExtractHashtags(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"#tag.", []string{"tag"}},
+		{"Ending a sentence with #Bitcoin, then #ETH!", []string{"bitcoin", "eth"}},
+		{"(#inParens) and \"#quoted\"", []string{"inparens", "quoted"}},
+		{"mid#word and abc#def aren't tags", nil},
+		{"C# and F# aren't tags either", nil},
+		{"#1 is all digits but #2024election isn't", []string{"2024election"}},
+		{"#snake_case_tag", []string{"snake_case_tag"}},
+		{"#tag#chained", []string{"tag"}},
+		{"a lone # or ## is nothing", nil},
+		{"full-width ＃タグ and #café", []string{"タグ", "café"}},
+		{"&#39; entities and a/#fragment", nil},
+		{"see https://example.com/page#section", nil},
+		{"#Same and #same", []string{"same", "same"}},
+	}
+	for _, tt := range tests {
+		if got := ExtractEntities(tt.text).Hashtags; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("hashtags in %q = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestAggregateTrends(t *testing.T) {
+	post := func(text string, likes int) SearchResult {
+		return SearchResult{Text: text, PublicMetrics: PublicMetrics{LikeCount: likes}}
+	}
+	items := []SearchResult{
+		post("#btc to the moon #BTC #btc @alice", 10),
+		post("#eth and #btc, says @Alice", 5),
+		post("#eth again https://example.com/a.", 1),
+		post("#sol https://example.com/a", 100),
+		post("no entities at all", 1000),
+	}
+
+	got := AggregateTrends(items, 0)
+	want := Trends{
+		// A tag repeated within a post counts once; ties on count are
+		// broken by engagement, then by value.
+		Hashtags: []EntityCount{
+			{Value: "btc", Count: 2, Engagement: 15},
+			{Value: "eth", Count: 2, Engagement: 6},
+			{Value: "sol", Count: 1, Engagement: 100},
+		},
+		Mentions: []EntityCount{{Value: "alice", Count: 2, Engagement: 15}},
+		URLs:     []EntityCount{{Value: "https://example.com/a", Count: 2, Engagement: 101}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AggregateTrends =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if top := AggregateTrends(items, 1).Hashtags; len(top) != 1 || top[0].Value != "btc" {
+		t.Errorf("top hashtag = %+v, want btc only", top)
+	}
+
+	empty := AggregateTrends(nil, 5)
+	if len(empty.Hashtags) != 0 || len(empty.Mentions) != 0 || len(empty.URLs) != 0 {
+		t.Errorf("AggregateTrends(nil) = %+v, want no entities", empty)
+	}
+	if empty.Hashtags == nil || empty.Mentions == nil || empty.URLs == nil {
+		t.Errorf("AggregateTrends(nil) = %+v, want empty lists that marshal as []", empty)
+	}
+}
+
+func TestExtractMentions(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"@alice", []string{"alice"}},
+		{"thanks @Alice, @bob_1! and (@carol)", []string{"alice", "bob_1", "carol"}},
+		{"@dave: hi @erin.", []string{"dave", "erin"}},
+		{"mail me at user@example.com", nil},
+		{"full-width ＠frank", []string{"frank"}},
+		{"@fifteen_chars_1 but not @sixteen_chars_12", []string{"fifteen_chars_1"}},
+		{"@josé stops at the accent", []string{"jos"}},
+		{"@émile isn't a valid handle", nil},
+		{"café@grace isn't a mention", nil},
+		{"lone @ sign", nil},
+		{"see https://example.com/@heidi", nil},
+	}
+	for _, tt := range tests {
+		if got := ExtractEntities(tt.text).Mentions; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("mentions in %q = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestExtractURLs(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"read https://example.com/a.", []string{"https://example.com/a"}},
+		{"(see http://example.com/b) and \"https://example.com/c\"", []string{"http://example.com/b", "https://example.com/c"}},
+		{"https://example.com/d?x=1&y=2, then https://example.com/e!?", []string{"https://example.com/d?x=1&y=2", "https://example.com/e"}},
+		{"https://example.com/page#section keeps its fragment", []string{"https://example.com/page#section"}},
+		{"https://example.com/ü/ñ", []string{"https://example.com/ü/ñ"}},
+		{"ftp://example.com and example.com aren't matched", nil},
+	}
+	for _, tt := range tests {
+		if got := ExtractEntities(tt.text).URLs; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("URLs in %q = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSearchWithEntities(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, `{"items":[{"id":"1","text":"#BTC up, says @Alice: https://example.com/x."},{"id":"2","text":"nothing here"}]}`)
+	})
+
+	resp, err := c.Search(context.Background(), "btc", 10)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if resp.Items[0].Entities != nil {
+		t.Errorf("Entities = %+v without WithEntities, want nil", resp.Items[0].Entities)
+	}
+
+	resp, err = c.Search(context.Background(), "btc", 10, WithEntities())
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	want := &Entities{Hashtags: []string{"btc"}, Mentions: []string{"alice"}, URLs: []string{"https://example.com/x"}}
+	if got := resp.Items[0].Entities; !reflect.DeepEqual(got, want) {
+		t.Errorf("Entities = %+v, want %+v", got, want)
+	}
+	if got := resp.Items[1].Entities; got == nil || !reflect.DeepEqual(*got, Entities{}) {
+		t.Errorf("Entities of a post without any = %+v, want empty", got)
+	}
+}
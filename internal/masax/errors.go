@@ -0,0 +1,105 @@
+package masax
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors for common API failure classes. Use errors.Is to test an
+// error returned by the client against them.
+var (
+	ErrUnauthorized = errors.New("masa X API: unauthorized")
+	ErrForbidden    = errors.New("masa X API: forbidden")
+	ErrRateLimited  = errors.New("masa X API: rate limited")
+	ErrUnavailable  = errors.New("masa X API: service unavailable")
+
+	// ErrEmptyResponse is returned when a successful response has no body
+	// where one is required, e.g. for a post lookup. Searches treat an empty
+	// body as no results instead.
+	ErrEmptyResponse = errors.New("masa X API: empty response")
+)
+
+// APIError is returned when the Masa X API responds with a non-2xx status.
+type APIError struct {
+	StatusCode  int
+	Code        string // API error code, empty if the body wasn't a structured error
+	Message     string // API error message, or the raw body (an excerpt if not JSON) for unstructured errors
+	ContentType string // Content-Type of a non-JSON error body, e.g. text/html from a gateway; empty otherwise
+
+	// Header holds diagnostic response headers such as rate-limit counters and
+	// request IDs. Only allowlisted headers are kept, so it never contains
+	// credentials or cookies.
+	Header http.Header
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("masa X API error (HTTP %d - %s): %s", e.StatusCode, e.Code, e.Message)
+	}
+	if e.ContentType != "" {
+		return fmt.Sprintf("masa X API request failed with HTTP status %d (%s response): %s", e.StatusCode, e.ContentType, e.Message)
+	}
+	return fmt.Sprintf("masa X API request failed with HTTP status %d: %s", e.StatusCode, e.Message)
+}
+
+// Is reports whether the error belongs to the class of the given sentinel.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUnavailable:
+		// The statuses retried by isRetryableStatus, not every 5xx: a 500 or
+		// 501 is a server bug rather than a temporary outage.
+		switch e.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+// diagnosticHeaders are the exact response headers copied into APIError.Header,
+// in addition to any rate-limit headers.
+var diagnosticHeaders = []string{"Retry-After", "X-Request-Id", "Date"}
+
+// newAPIError builds an APIError from a non-2xx response.
+func newAPIError(statusCode int, header http.Header, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, Message: string(body), Header: diagnosticHeader(header)}
+	var errResp ErrorResponse
+	if json.Unmarshal(body, &errResp) == nil && errResp.Error.Message != "" {
+		apiErr.Code = errResp.Error.Code
+		apiErr.Message = errResp.Error.Message
+		return apiErr
+	}
+	if contentType := header.Get("Content-Type"); contentType != "" && !isJSONContentType(contentType) && !looksLikeJSON(body) {
+		apiErr.ContentType = contentType
+		apiErr.Message = bodyExcerpt(body)
+	}
+	return apiErr
+}
+
+// diagnosticHeader returns the allowlisted subset of h: the diagnosticHeaders
+// and anything mentioning a rate limit, e.g. X-RateLimit-Remaining.
+func diagnosticHeader(h http.Header) http.Header {
+	kept := make(http.Header)
+	for name, values := range h {
+		lower := strings.ToLower(name)
+		if strings.Contains(lower, "ratelimit") || strings.Contains(lower, "rate-limit") {
+			kept[name] = append([]string(nil), values...)
+		}
+	}
+	for _, name := range diagnosticHeaders {
+		if v := h.Values(name); len(v) > 0 {
+			kept[http.CanonicalHeaderKey(name)] = append([]string(nil), v...)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,61 @@
+package masax
+
+import "strings"
+
+// Query operators understood by the API for excluding post types.
+const (
+	excludeRetweetsOperator = "-is:retweet"
+	excludeRepliesOperator  = "-is:reply"
+)
+
+// IsRetweet reports whether a result looks like a retweet, i.e. its text
+// starts with the conventional "RT @user" prefix.
+func IsRetweet(r SearchResult) bool {
+	return strings.HasPrefix(r.Text, "RT @")
+}
+
+// IsReply reports whether a result looks like a reply, i.e. its text starts
+// with an @mention.
+func IsReply(r SearchResult) bool {
+	return strings.HasPrefix(r.Text, "@")
+}
+
+// WithExcludeRetweets drops retweets. The -is:retweet operator is added to the
+// query so the API excludes them, and IsRetweet filters any that slip
+// through client-side.
+func WithExcludeRetweets() SearchOption {
+	return func(o *searchOptions) {
+		o.queryOperators = append(o.queryOperators, excludeRetweetsOperator)
+		o.filters = append(o.filters, func(r SearchResult) bool { return !IsRetweet(r) })
+	}
+}
+
+// WithExcludeReplies drops replies. The -is:reply operator is added to the
+// query so the API excludes them, and IsReply filters any that slip through
+// client-side.
+func WithExcludeReplies() SearchOption {
+	return func(o *searchOptions) {
+		o.queryOperators = append(o.queryOperators, excludeRepliesOperator)
+		o.filters = append(o.filters, func(r SearchResult) bool { return !IsReply(r) })
+	}
+}
+
+// appendOperators adds each operator to query unless it's already present.
+func appendOperators(query string, operators []string) string {
+	for _, op := range operators {
+		if !containsTerm(query, op) {
+			query = strings.TrimSpace(query) + " " + op
+		}
+	}
+	return query
+}
+
+// containsTerm reports whether term appears as a whitespace-separated token.
+func containsTerm(query, term string) bool {
+	for _, field := range strings.Fields(query) {
+		if field == term {
+			return true
+		}
+	}
+	return false
+}
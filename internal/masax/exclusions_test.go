@@ -0,0 +1,93 @@
+package masax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestIsRetweetAndIsReply(t *testing.T) {
+	tests := []struct {
+		text           string
+		retweet, reply bool
+	}{
+		{"RT @alice: rates are going up", true, false},
+		{"@bob I disagree", false, true},
+		{"RT@alice no space", false, false},
+		{"rt @alice lowercase", false, false},
+		{"Good thread, RT @alice", false, false},
+		{"email me at a@b.com", false, false},
+		{" @bob leading space", false, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		r := SearchResult{Text: tt.text}
+		if got := IsRetweet(r); got != tt.retweet {
+			t.Errorf("IsRetweet(%q) = %t, want %t", tt.text, got, tt.retweet)
+		}
+		if got := IsReply(r); got != tt.reply {
+			t.Errorf("IsReply(%q) = %t, want %t", tt.text, got, tt.reply)
+		}
+	}
+}
+
+func TestAppendOperators(t *testing.T) {
+	tests := []struct {
+		query     string
+		operators []string
+		want      string
+	}{
+		{"bitcoin", nil, "bitcoin"},
+		{"bitcoin", []string{excludeRetweetsOperator}, "bitcoin -is:retweet"},
+		{"bitcoin ", []string{excludeRetweetsOperator, excludeRepliesOperator}, "bitcoin -is:retweet -is:reply"},
+		{"bitcoin -is:retweet", []string{excludeRetweetsOperator, excludeRepliesOperator}, "bitcoin -is:retweet -is:reply"},
+		{"bitcoin -is:retweets", []string{excludeRetweetsOperator}, "bitcoin -is:retweets -is:retweet"},
+	}
+	for _, tt := range tests {
+		if got := appendOperators(tt.query, tt.operators); got != tt.want {
+			t.Errorf("appendOperators(%q, %q) = %q, want %q", tt.query, tt.operators, got, tt.want)
+		}
+	}
+}
+
+func TestSearchExclusions(t *testing.T) {
+	const page = `{"items":[
+		{"id":"1","text":"original post"},
+		{"id":"2","text":"RT @alice: slipped through"},
+		{"id":"3","text":"@bob a reply"},
+		{"id":"4","text":"another original, RT @alice"}
+	]}`
+	tests := []struct {
+		name      string
+		opts      []SearchOption
+		wantQuery string
+		wantIDs   string
+	}{
+		{"default", nil, "markets", "1,2,3,4"},
+		{"exclude retweets", []SearchOption{WithExcludeRetweets()}, "markets -is:retweet", "1,3,4"},
+		{"exclude replies", []SearchOption{WithExcludeReplies()}, "markets -is:reply", "1,2,4"},
+		{"exclude both", []SearchOption{WithExcludeRetweets(), WithExcludeReplies()}, "markets -is:retweet -is:reply", "1,4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery string
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var req SearchRequest
+				json.NewDecoder(r.Body).Decode(&req)
+				gotQuery = req.Query
+				writeJSON(w, page)
+			})
+			resp, err := c.Search(context.Background(), "markets", 10, tt.opts...)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if gotQuery != tt.wantQuery {
+				t.Errorf("server received query %q, want %q", gotQuery, tt.wantQuery)
+			}
+			if got := itemIDs(resp.Items); got != tt.wantIDs {
+				t.Errorf("IDs = %s, want %s", got, tt.wantIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package masax
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvHeader lists the columns written by WriteCSV.
+var csvHeader = []string{"id", "author_id", "created_at", "text", "like_count", "retweet_count", "reply_count", "quote_count", "url"}
+
+// WriteCSV writes the response items to w as RFC 4180 CSV with a header row.
+// Fields containing commas, quotes or newlines are quoted and escaped.
+func WriteCSV(w io.Writer, resp *SearchResponse) error {
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = true // RFC 4180 line endings
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, item := range resp.Items {
+		record := []string{
+			item.ID,
+			item.AuthorID,
+			item.CreatedAt.Format(time.RFC3339),
+			item.Text,
+			strconv.Itoa(item.PublicMetrics.LikeCount),
+			strconv.Itoa(item.PublicMetrics.RetweetCount),
+			strconv.Itoa(item.PublicMetrics.ReplyCount),
+			strconv.Itoa(item.PublicMetrics.QuoteCount),
+			item.URL,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ndjsonMetadataLine wraps the metadata emitted as the last NDJSON line so
+// consumers can tell it apart from result lines.
+type ndjsonMetadataLine struct {
+	Metadata SearchMetadata `json:"_metadata"`
+}
+
+// WriteNDJSON writes each response item to w as a standalone JSON object on
+// its own line. If includeMetadata is set, a final line of the form
+// {"_metadata": {...}} carries the response metadata.
+func WriteNDJSON(w io.Writer, resp *SearchResponse, includeMetadata bool) error {
+	enc := json.NewEncoder(w) // Encode terminates each value with a newline
+	for _, item := range resp.Items {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+	if includeMetadata {
+		return enc.Encode(ndjsonMetadataLine{Metadata: resp.Metadata})
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package masax
+
+import (
+	"context"
+	"fmt"
+)
+
+// maxFilteredPages bounds how many pages Search fetches while trying to fill
+// max_results with results that pass the configured filters.
+const maxFilteredPages = 5
+
+// FilterFunc reports whether a search result should be kept.
+type FilterFunc func(SearchResult) bool
+
+// WithFilter adds a filter applied to the results of every search made by the
+// client. Results must pass all filters to be returned.
+func WithFilter(filter FilterFunc) ClientOption {
+	return func(c *Client) {
+		if filter != nil {
+			c.filters = append(c.filters, filter)
+		}
+	}
+}
+
+// WithItemFilter adds a filter for a single call, in addition to any
+// client-wide filters.
+func WithItemFilter(filter FilterFunc) SearchOption {
+	return func(o *searchOptions) {
+		if filter != nil {
+			o.filters = append(o.filters, filter)
+		}
+	}
+}
+
+// MinEngagement returns a filter keeping results with at least minLikes likes
+// and minRetweets retweets. Both bounds are inclusive.
+func MinEngagement(minLikes, minRetweets int) FilterFunc {
+	return func(r SearchResult) bool {
+		return r.PublicMetrics.LikeCount >= minLikes && r.PublicMetrics.RetweetCount >= minRetweets
+	}
+}
+
+// VerifiedOnly returns a filter keeping only results whose author is known
+// to be verified. Results without AuthorVerified are dropped, so if the API
+// doesn't report verification the filter keeps nothing.
+func VerifiedOnly() FilterFunc {
+	return func(r SearchResult) bool {
+		return r.AuthorVerified != nil && *r.AuthorVerified
+	}
+}
+
+// filter removes items rejected by any of the configured filters, in place.
+func (o searchOptions) filter(items []SearchResult) []SearchResult {
+	if len(o.filters) == 0 {
+		return items
+	}
+	kept := items[:0]
+	for _, item := range items {
+		if o.keep(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+func (o searchOptions) keep(item SearchResult) bool {
+	for _, f := range o.filters {
+		if !f(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// searchFiltered fetches pages until it has searchReq.MaxResults results that
+// pass the filters and deduplication, the results run out, or
+// maxFilteredPages is reached. Filtering happens before truncation so
+// dropped results don't eat into max_results. If a later page fails, the
+// results collected so far are returned with the error.
+func (c *Client) searchFiltered(ctx context.Context, searchReq SearchRequest, opts searchOptions) (*SearchResponse, error) {
+	if err := opts.applyTo(&searchReq); err != nil {
+		return nil, err
+	}
+
+	all := &SearchResponse{}
+	seenTokens := make(map[string]bool)
+	for page := 1; page <= maxFilteredPages; page++ {
+		pageResp, err := c.fetch(ctx, searchReq, opts)
+		if err != nil {
+			err = fmt.Errorf("failed to fetch page %d: %w", page, err)
+			if page == 1 {
+				return nil, err
+			}
+			return opts.finishFiltered(all, searchReq.MaxResults), err // Partial results, as from SearchAll
+		}
+		// Deduplicate across pages too, as reposts often land on different pages
+		all.Items = opts.reduce(append(all.Items, pageResp.Items...))
+		all.Metadata = pageResp.Metadata
+		all.Raw = pageResp.Raw
+		all.mergeFreshness(pageResp, page == 1)
+		opts.reportProgress(Progress{Pages: page, Results: min(len(all.Items), searchReq.MaxResults), Target: searchReq.MaxResults})
+
+		next := pageResp.Metadata.NextToken
+		if len(all.Items) >= searchReq.MaxResults || next == "" || len(pageResp.Items) == 0 || seenTokens[next] {
+			break
+		}
+		seenTokens[next] = true
+		searchReq.NextToken = next
+	}
+
+	return opts.finishFiltered(all, searchReq.MaxResults), nil
+}
+
+// finishFiltered orders, truncates and enriches the results collected by
+// searchFiltered.
+func (o searchOptions) finishFiltered(all *SearchResponse, maxResults int) *SearchResponse {
+	o.order(all.Items)
+	if len(all.Items) > maxResults {
+		all.Items = all.Items[:maxResults]
+	}
+	o.enrich(all.Items)
+	return all
+}
@@ -0,0 +1,94 @@
+package masax
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMinEngagement(t *testing.T) {
+	post := func(likes, retweets int) SearchResult {
+		return SearchResult{PublicMetrics: PublicMetrics{LikeCount: likes, RetweetCount: retweets}}
+	}
+	tests := []struct {
+		name                  string
+		minLikes, minRetweets int
+		post                  SearchResult
+		want                  bool
+	}{
+		{"no bounds", 0, 0, post(0, 0), true},
+		{"likes one below", 10, 0, post(9, 100), false},
+		{"likes at bound", 10, 0, post(10, 0), true},
+		{"likes one above", 10, 0, post(11, 0), true},
+		{"retweets one below", 0, 5, post(100, 4), false},
+		{"retweets at bound", 0, 5, post(0, 5), true},
+		{"both at bounds", 10, 5, post(10, 5), true},
+		{"likes at bound, retweets below", 10, 5, post(10, 4), false},
+		{"retweets at bound, likes below", 10, 5, post(9, 5), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MinEngagement(tt.minLikes, tt.minRetweets)(tt.post); got != tt.want {
+				t.Errorf("MinEngagement(%d, %d) on %+v = %t, want %t", tt.minLikes, tt.minRetweets, tt.post.PublicMetrics, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchFiltersBeforeTruncation(t *testing.T) {
+	// Two pages of four posts; only those with even IDs reach 10 likes.
+	var requested []int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req SearchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		requested = append(requested, req.MaxResults)
+
+		resp := SearchResponse{}
+		offset, _ := strconv.Atoi(req.NextToken)
+		for id := offset + 1; id <= offset+4; id++ {
+			likes := 9
+			if id%2 == 0 {
+				likes = 10
+			}
+			resp.Items = append(resp.Items, SearchResult{ID: strconv.Itoa(id), PublicMetrics: PublicMetrics{LikeCount: likes}})
+		}
+		if offset == 0 {
+			resp.Metadata.NextToken = "4"
+		}
+		body, _ := json.Marshal(resp)
+		writeJSON(w, string(body))
+	}, WithFilter(MinEngagement(10, 0)))
+
+	resp, err := c.Search(context.Background(), "engaged", 3)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if got := itemIDs(resp.Items); got != "2,4,6" {
+		t.Errorf("IDs = %s, want 2,4,6: filtering must happen before truncating to max_results", got)
+	}
+	if len(requested) != 2 {
+		t.Errorf("server got %d requests, want 2 to fill max_results", len(requested))
+	}
+
+	// A per-call filter narrows the client-wide one.
+	resp, err = c.Search(context.Background(), "engaged", 3, WithItemFilter(func(r SearchResult) bool { return r.ID != "2" }))
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if got := itemIDs(resp.Items); got != "4,6,8" {
+		t.Errorf("IDs with a per-call filter = %s, want 4,6,8", got)
+	}
+}
+
+// itemIDs returns the IDs of items joined with commas, for comparing
+// results and their order.
+func itemIDs(items []SearchResult) string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return strings.Join(ids, ",")
+}
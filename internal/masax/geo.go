@@ -0,0 +1,102 @@
+package masax
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxRadiusKm is the largest radius the point_radius operator accepts
+// (25 miles).
+const MaxRadiusKm = 40
+
+// PointRadius is a circle on the map: a center in decimal degrees and a
+// radius in kilometers.
+type PointRadius struct {
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"lon"`
+	RadiusKm float64 `json:"radius_km"`
+}
+
+// ParsePointRadius parses "lat,lon,radius_km", e.g. "40.7128,-74.0060,10".
+func ParsePointRadius(s string) (PointRadius, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 {
+		return PointRadius{}, fmt.Errorf("invalid point radius %q: expected \"lat,lon,radius_km\"", s)
+	}
+	var vals [3]float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return PointRadius{}, fmt.Errorf("invalid point radius %q: %q is not a number", s, strings.TrimSpace(part))
+		}
+		vals[i] = v
+	}
+	p := PointRadius{Lat: vals[0], Lon: vals[1], RadiusKm: vals[2]}
+	if err := p.validate(); err != nil {
+		return PointRadius{}, err
+	}
+	return p, nil
+}
+
+// validate checks the coordinates and radius are in range.
+func (p PointRadius) validate() error {
+	if !(p.Lat >= -90 && p.Lat <= 90) { // Also rejects NaN
+		return fmt.Errorf("invalid latitude %g: must be between -90 and 90", p.Lat)
+	}
+	if !(p.Lon >= -180 && p.Lon <= 180) {
+		return fmt.Errorf("invalid longitude %g: must be between -180 and 180", p.Lon)
+	}
+	if !(p.RadiusKm > 0 && p.RadiusKm <= MaxRadiusKm) {
+		return fmt.Errorf("invalid radius %gkm: must be greater than 0 and at most %dkm", p.RadiusKm, MaxRadiusKm)
+	}
+	return nil
+}
+
+// operator renders p as a point_radius query operator, which takes the
+// longitude first.
+func (p PointRadius) operator() string {
+	return fmt.Sprintf("point_radius:[%s %s %skm]",
+		strconv.FormatFloat(p.Lon, 'f', -1, 64),
+		strconv.FormatFloat(p.Lat, 'f', -1, 64),
+		strconv.FormatFloat(p.RadiusKm, 'f', -1, 64))
+}
+
+// WithPointRadius restricts results to geo-tagged posts within p, using the
+// point_radius query operator. Only posts carrying location data can match,
+// and if the API doesn't support the operator it may ignore it or reject the
+// query; results carry no location, so the filter can't be checked
+// client-side.
+func WithPointRadius(p PointRadius) SearchOption {
+	return func(o *searchOptions) {
+		o.pointRadius = &p
+	}
+}
+
+// WithPlace restricts results to posts tagged with the named place, e.g.
+// "new york city", using the place query operator. The same caveats as for
+// WithPointRadius apply.
+func WithPlace(name string) SearchOption {
+	return func(o *searchOptions) {
+		o.place = name
+	}
+}
+
+// geoOperators validates the geo options and returns their query operators.
+func (o searchOptions) geoOperators() ([]string, error) {
+	var ops []string
+	if o.place != "" {
+		place := strings.TrimSpace(o.place)
+		if place == "" || strings.ContainsAny(place, "\"\n") {
+			return nil, fmt.Errorf("invalid place %q: must be a non-empty name without double quotes", o.place)
+		}
+		ops = append(ops, fmt.Sprintf("place:%q", place))
+	}
+	if o.pointRadius != nil {
+		if err := o.pointRadius.validate(); err != nil {
+			return nil, err
+		}
+		ops = append(ops, o.pointRadius.operator())
+	}
+	return ops, nil
+}
@@ -0,0 +1,69 @@
+package masax
+
+import (
+	"net/http"
+	"sort"
+)
+
+// protectedHeaders are always set by the client and can't be overridden with
+// WithHeader or WithHeaders.
+var protectedHeaders = map[string]bool{
+	"Authorization": true,
+	"Content-Type":  true,
+}
+
+// header is a custom header added to every request.
+type header struct {
+	key   string
+	value string
+}
+
+// WithHeader adds a custom header to every request, e.g. a tenant ID required
+// by a gateway. Custom headers override User-Agent, Accept and
+// Accept-Encoding but not Authorization, Content-Type or the header set by
+// WithAuthScheme. Headers are applied in the order given.
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		if key != "" {
+			c.headers = append(c.headers, header{key: http.CanonicalHeaderKey(key), value: value})
+		}
+	}
+}
+
+// WithHeaders adds each header in headers, as WithHeader does, in sorted key
+// order so the result doesn't depend on map iteration.
+func WithHeaders(headers map[string]string) ClientOption {
+	return func(c *Client) {
+		keys := make([]string, 0, len(headers))
+		for key := range headers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			WithHeader(key, headers[key])(c)
+		}
+	}
+}
+
+// setHeaders sets the default, custom and protected headers on req, in that
+// order, so custom headers win over defaults but never over protected ones.
+// The request ID from req's context, if any, overrides a custom
+// X-Request-Id.
+func (c *Client) setHeaders(req *http.Request) {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	for _, h := range c.headers {
+		if !protectedHeaders[h.key] && h.key != c.auth.headerName() {
+			req.Header.Set(h.key, h.value)
+		}
+	}
+
+	if id := RequestIDFromContext(req.Context()); id != "" {
+		req.Header.Set(requestIDHeader, id)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	c.setAuth(req)
+}
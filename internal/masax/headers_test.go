@@ -0,0 +1,102 @@
+package masax
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+// receivedHeaders runs a search with opts and returns the headers the
+// server received.
+func receivedHeaders(t *testing.T, ctx context.Context, opts ...ClientOption) http.Header {
+	t.Helper()
+	var got http.Header
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		writeJSON(w, `{"items":[]}`)
+	}, opts...)
+	if _, err := c.Search(ctx, "headers", 10); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	return got
+}
+
+func TestCustomHeaders(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ClientOption
+		ctx  context.Context
+		want map[string]string // "" means the header must be absent
+	}{
+		{
+			name: "defaults",
+			want: map[string]string{
+				"Authorization": "Bearer test-key",
+				"Content-Type":  "application/json",
+				"Accept":        "application/json",
+				"User-Agent":    "masax-mcp/0.1.0",
+			},
+		},
+		{
+			name: "custom headers",
+			opts: []ClientOption{
+				WithHeader("x-tenant-id", "acme"),
+				WithHeaders(map[string]string{"X-Region": "eu", "X-Trace": "on"}),
+			},
+			want: map[string]string{"X-Tenant-Id": "acme", "X-Region": "eu", "X-Trace": "on"},
+		},
+		{
+			name: "later header wins",
+			opts: []ClientOption{WithHeader("X-Tenant-Id", "first"), WithHeader("X-Tenant-Id", "second")},
+			want: map[string]string{"X-Tenant-Id": "second"},
+		},
+		{
+			name: "user agent option",
+			opts: []ClientOption{WithUserAgent("reports-bot/2.0")},
+			want: map[string]string{"User-Agent": "reports-bot/2.0"},
+		},
+		{
+			name: "user agent header overrides option",
+			opts: []ClientOption{WithUserAgent("reports-bot/2.0"), WithHeader("User-Agent", "gateway/1.0")},
+			want: map[string]string{"User-Agent": "gateway/1.0"},
+		},
+		{
+			name: "authorization and content type protected",
+			opts: []ClientOption{
+				WithHeader("Authorization", "Bearer stolen"),
+				WithHeaders(map[string]string{"content-type": "text/plain"}),
+			},
+			want: map[string]string{"Authorization": "Bearer test-key", "Content-Type": "application/json"},
+		},
+		{
+			name: "auth scheme header protected",
+			opts: []ClientOption{WithAuthScheme(HeaderAuth("X-API-Key")), WithHeader("X-Api-Key", "stolen")},
+			want: map[string]string{"X-Api-Key": "test-key", "Authorization": ""},
+		},
+		{
+			name: "context request id overrides header",
+			opts: []ClientOption{WithHeader(requestIDHeader, "from-header")},
+			ctx:  ContextWithRequestID(context.Background(), "from-context"),
+			want: map[string]string{requestIDHeader: "from-context"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			got := receivedHeaders(t, ctx, tt.opts...)
+			for key, want := range tt.want {
+				values := got.Values(key)
+				if want == "" {
+					if len(values) != 0 {
+						t.Errorf("%s = %q, want none", key, values)
+					}
+				} else if len(values) != 1 || values[0] != want {
+					t.Errorf("%s = %q, want %q", key, values, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package masax
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+// SearchEvent describes the outcome of fetching one page of results, either
+// from the API or from the cache.
+type SearchEvent struct {
+	Query      string
+	StatusCode int           // HTTP status of the last attempt; 0 if no response was received
+	Cached     bool          // Served from the response cache without an API call
+	Retries    int           // Attempts made beyond the first
+	Duration   time.Duration // Time spent on API calls, including retries; 0 for cache hits
+	Err        error
+}
+
+// SearchHook is called after every fetch. Hooks run synchronously on the
+// calling goroutine and should return quickly.
+type SearchHook func(ctx context.Context, event SearchEvent)
+
+// WithSearchHook registers a hook that observes every fetch, e.g. to record
+// metrics. Multiple hooks are called in registration order.
+func WithSearchHook(hook SearchHook) ClientOption {
+	return func(c *Client) {
+		if hook != nil {
+			c.hooks = append(c.hooks, hook)
+		}
+	}
+}
+
+// emit passes event to the registered hooks.
+func (c *Client) emit(ctx context.Context, event SearchEvent) {
+	for _, hook := range c.hooks {
+		hook(ctx, event)
+	}
+}
+
+// ErrorCategory classifies an error returned by the client as "auth",
+// "rate_limited", "circuit_open", "timeout", "canceled", "api", "network"
+// or "unknown", suitable for diagnostics and metric labels.
+func ErrorCategory(err error) string {
+	var netErr net.Error
+	var apiErr *APIError
+	switch {
+	case errors.Is(err, ErrUnauthorized), errors.Is(err, ErrForbidden):
+		return "auth"
+	case errors.Is(err, ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.As(err, &apiErr):
+		return "api"
+	case errors.As(err, &netErr):
+		return "network"
+	}
+	return "unknown"
+}
@@ -0,0 +1,50 @@
+package masax
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ErrInsecureBaseURL is returned by NewClient, wrapped, for a plaintext
+// base URL without WithAllowInsecure.
+var ErrInsecureBaseURL = errors.New("masa X: insecure base URL")
+
+// WithAllowInsecure permits a plain http base URL on a non-loopback host,
+// which sends the API key unencrypted. Without it NewClient rejects such
+// URLs with ErrInsecureBaseURL. Loopback hosts such as localhost and
+// 127.0.0.1 are always allowed, for local proxies and test servers.
+func WithAllowInsecure() ClientOption {
+	return func(c *Client) {
+		c.allowInsecure = true
+	}
+}
+
+// checkBaseURL rejects a base URL that isn't https, unless it's on a
+// loopback host or WithAllowInsecure is set.
+func (c *Client) checkBaseURL() error {
+	u, err := url.Parse(c.apiBaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL %q: %w", c.apiBaseURL, err)
+	}
+	switch {
+	case u.Scheme == "https", c.allowInsecure:
+		return nil
+	case u.Scheme != "http":
+		return fmt.Errorf("invalid base URL %q: scheme must be https or http", u.Redacted())
+	case isLoopbackHost(u.Hostname()):
+		return nil
+	}
+	return fmt.Errorf("%w: %s would send the API key in plaintext; use https or WithAllowInsecure", ErrInsecureBaseURL, u.Redacted())
+}
+
+// isLoopbackHost reports whether host is localhost or a loopback IP.
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
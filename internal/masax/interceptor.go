@@ -0,0 +1,88 @@
+package masax
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// RequestInterceptor inspects or modifies an API request just before it is
+// sent, e.g. to sign it or add tracing headers. A non-nil error fails the
+// request without retrying.
+type RequestInterceptor func(*http.Request) error
+
+// WithRequestInterceptor registers an interceptor for every HTTP request
+// sent by searches, pings and lookups. Multiple interceptors run in
+// registration order.
+//
+// Interceptors run after the client has set all its headers, including
+// Authorization and those from WithHeader, so they can override any of
+// them. They run once per attempt, so a retried request is intercepted
+// again; a body they read must be restored for the send. Searches served
+// from the cache send no request.
+func WithRequestInterceptor(interceptor RequestInterceptor) ClientOption {
+	return func(c *Client) {
+		if interceptor != nil {
+			c.requestInterceptors = append(c.requestInterceptors, interceptor)
+		}
+	}
+}
+
+// interceptRequest runs the request interceptors over req.
+func (c *Client) interceptRequest(req *http.Request) error {
+	for _, interceptor := range c.requestInterceptors {
+		if err := interceptor(req); err != nil {
+			return fmt.Errorf("request interceptor: %w", err)
+		}
+	}
+	return nil
+}
+
+// ResponseInterceptor inspects or modifies an API response before the
+// client parses it. It may change the status code and headers, or replace
+// the body. A non-nil error fails the request without retrying.
+type ResponseInterceptor func(*http.Response) error
+
+// WithResponseInterceptor registers an interceptor for every HTTP response
+// received by searches, pings and lookups. Multiple interceptors run in
+// registration order.
+//
+// Interceptors run once per attempt, so a retried request is intercepted
+// for each response, including the error responses that lead to a retry;
+// retry decisions use the status code as the interceptors leave it. The body
+// has already been read and decompressed, and Content-Encoding removed.
+// Responses served from the cache, or shared with an identical in-flight
+// search, are not intercepted again, and cached responses are stored as the
+// interceptors left them.
+func WithResponseInterceptor(interceptor ResponseInterceptor) ClientOption {
+	return func(c *Client) {
+		if interceptor != nil {
+			c.responseInterceptors = append(c.responseInterceptors, interceptor)
+		}
+	}
+}
+
+// intercept runs the response interceptors over resp, whose body has been
+// read into body, and returns the body as they leave it.
+func (c *Client) intercept(resp *http.Response, body []byte) ([]byte, error) {
+	if len(c.responseInterceptors) == 0 {
+		return body, nil
+	}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	resp.ContentLength = int64(len(body))
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	for _, interceptor := range c.responseInterceptors {
+		if err := interceptor(resp); err != nil {
+			return nil, fmt.Errorf("response interceptor: %w", err)
+		}
+	}
+	body, err := readLimited(resp.Body, c.maxResponseBytes)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read intercepted response body: %w", err)
+	}
+	return body, nil
+}
@@ -0,0 +1,153 @@
+package masax
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestInterceptors(t *testing.T) {
+	var got []http.Header
+	var failFirst atomic.Bool
+	failFirst.Store(true)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Clone())
+		if failFirst.Swap(false) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, `{"items":[]}`)
+	},
+		WithRetry(2, time.Millisecond),
+		WithCache(10, time.Minute),
+		WithHeader("X-Tenant-Id", "acme"),
+		WithRequestInterceptor(func(req *http.Request) error {
+			// Sees the client's headers, including custom ones
+			req.Header.Set("X-Signature", "signed:"+req.Header.Get("Authorization")+":"+req.Header.Get("X-Tenant-Id"))
+			return nil
+		}),
+		WithRequestInterceptor(func(req *http.Request) error {
+			// Runs second, so it can override the first and the client
+			req.Header.Set("X-Order", req.Header.Get("X-Signature")+"|second")
+			req.Header.Set("Authorization", "Signed key-1")
+			return nil
+		}),
+	)
+
+	if _, err := c.Search(context.Background(), "intercepted", 10); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("server got %d requests, want 2: a 503 and its retry", len(got))
+	}
+	for i, header := range got {
+		if sig := header.Get("X-Signature"); sig != "signed:Bearer test-key:acme" {
+			t.Errorf("attempt %d: X-Signature = %q", i+1, sig)
+		}
+		if order := header.Get("X-Order"); order != "signed:Bearer test-key:acme|second" {
+			t.Errorf("attempt %d: X-Order = %q, want the interceptors in registration order", i+1, order)
+		}
+		if auth := header.Get("Authorization"); auth != "Signed key-1" {
+			t.Errorf("attempt %d: Authorization = %q, want the interceptor's", i+1, auth)
+		}
+	}
+
+	// A cached search sends nothing, so nothing is intercepted.
+	if _, err := c.Search(context.Background(), "intercepted", 10); err != nil {
+		t.Fatalf("cached Search: %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("server got %d requests after a cached search, want 2", len(got))
+	}
+}
+
+func TestRequestInterceptorError(t *testing.T) {
+	errUnsigned := errors.New("no signing key")
+	var requests, intercepted atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		writeJSON(w, `{"items":[]}`)
+	},
+		WithRetry(3, time.Millisecond),
+		WithRequestInterceptor(func(*http.Request) error {
+			intercepted.Add(1)
+			return errUnsigned
+		}),
+	)
+
+	_, err := c.Search(context.Background(), "unsigned", 10)
+	if !errors.Is(err, errUnsigned) {
+		t.Fatalf("Search error = %v, want the interceptor's error", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("server got %d requests, want none", n)
+	}
+	if n := intercepted.Load(); n != 1 {
+		t.Errorf("interceptor ran %d times, want once: its errors aren't retried", n)
+	}
+}
+
+func TestResponseInterceptors(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("X-Envelope", "data")
+		w.Write(gzipped(t, `{"data":{"items":[{"id":"1","text":"wrapped"}]}}`))
+	},
+		WithResponseInterceptor(func(resp *http.Response) error {
+			if enc := resp.Header.Get("Content-Encoding"); enc != "" {
+				t.Errorf("Content-Encoding = %q, want it removed after decompression", enc)
+			}
+			// Unwraps the envelope named by the header
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return err
+			}
+			prefix := `{"` + resp.Header.Get("X-Envelope") + `":`
+			unwrapped := strings.TrimSuffix(strings.TrimPrefix(string(body), prefix), "}")
+			resp.Body = io.NopCloser(strings.NewReader(unwrapped))
+			return nil
+		}),
+	)
+
+	resp, err := c.Search(context.Background(), "wrapped", 10)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].Text != "wrapped" {
+		t.Errorf("Items = %+v, want the unwrapped result", resp.Items)
+	}
+}
+
+func TestResponseInterceptorStatus(t *testing.T) {
+	var requests atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		w.Header().Set("X-Overloaded", "true")
+		if n > 1 {
+			w.Header().Del("X-Overloaded")
+		}
+		writeJSON(w, `{"items":[{"id":"1"}]}`)
+	},
+		WithRetry(3, time.Millisecond),
+		WithResponseInterceptor(func(resp *http.Response) error {
+			// A gateway reporting overload in a header rather than the status
+			if resp.Header.Get("X-Overloaded") == "true" {
+				resp.StatusCode = http.StatusServiceUnavailable
+			}
+			return nil
+		}),
+	)
+
+	if _, err := c.Search(context.Background(), "overloaded", 10); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("server got %d requests, want 2: the rewritten 503 is retried", n)
+	}
+}
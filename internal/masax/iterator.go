@@ -0,0 +1,68 @@
+package masax
+
+import (
+	"context"
+	"io"
+)
+
+// SearchIterator yields search results one at a time, fetching further pages
+// via next_token as needed. It is not safe for concurrent use.
+type SearchIterator struct {
+	client     *Client
+	query      string
+	pageSize   int
+	nextToken  string
+	seenTokens map[string]bool
+	buf        []SearchResult
+	done       bool
+	err        error
+}
+
+// Iterate returns an iterator over all results for query, requesting pageSize
+// results per page (capped at the client's max results limit). No request is
+// made until the first call to Next.
+func (c *Client) Iterate(query string, pageSize int) *SearchIterator {
+	return &SearchIterator{
+		client:     c,
+		query:      query,
+		pageSize:   min(pageSize, c.maxResultsLimit),
+		seenTokens: make(map[string]bool),
+	}
+}
+
+// Next returns the next result. It returns io.EOF once all pages have been
+// consumed. An API error is returned as soon as it occurs and is returned
+// again by every subsequent call.
+func (it *SearchIterator) Next(ctx context.Context) (*SearchResult, error) {
+	for len(it.buf) == 0 {
+		if it.err != nil {
+			return nil, it.err
+		}
+		if it.done {
+			return nil, io.EOF
+		}
+		it.fetch(ctx)
+	}
+	result := it.buf[0]
+	it.buf = it.buf[1:]
+	return &result, nil
+}
+
+// fetch loads the next page into the buffer, recording an error or the end of
+// the result set on the iterator.
+func (it *SearchIterator) fetch(ctx context.Context) {
+	page, err := it.client.SearchPage(ctx, it.query, it.pageSize, it.nextToken)
+	if err != nil {
+		it.err = err
+		return
+	}
+	it.buf = page.Items
+
+	// Same termination rules as SearchAll, including the repeated-token guard.
+	it.nextToken = page.Metadata.NextToken
+	if it.nextToken == "" || len(page.Items) == 0 || it.seenTokens[it.nextToken] {
+		it.done = true
+		return
+	}
+	it.seenTokens[it.nextToken] = true
+}
@@ -0,0 +1,32 @@
+package masax
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ExcludeTerms returns a filter dropping results whose text contains any of
+// terms, ignoring case. With wholeWord, a term only matches where it isn't
+// part of a longer word, so "ape" drops "ape NFT" but not "grape".
+// Otherwise it matches anywhere. Empty terms are ignored; with none left,
+// the filter keeps every result.
+func ExcludeTerms(terms []string, wholeWord bool) FilterFunc {
+	var quoted []string
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			quoted = append(quoted, regexp.QuoteMeta(term))
+		}
+	}
+	if len(quoted) == 0 {
+		return func(SearchResult) bool { return true }
+	}
+	expr := "(?i)(?:" + strings.Join(quoted, "|") + ")"
+	if wholeWord {
+		// Not \b, which only knows ASCII letters
+		expr = `(?i)(?:^|[^\p{L}\p{N}_])(?:` + strings.Join(quoted, "|") + `)(?:$|[^\p{L}\p{N}_])`
+	}
+	pattern := regexp.MustCompile(expr) // Quoted terms always compile
+	return func(r SearchResult) bool {
+		return !pattern.MatchString(r.Text)
+	}
+}
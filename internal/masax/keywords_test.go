@@ -0,0 +1,63 @@
+package masax
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestExcludeTerms(t *testing.T) {
+	tests := []struct {
+		name      string
+		terms     []string
+		wholeWord bool
+		text      string
+		want      bool // Kept
+	}{
+		{"no terms", nil, false, "free airdrop", true},
+		{"only blank terms", []string{"", "  "}, false, "free airdrop", true},
+		{"substring", []string{"airdrop"}, false, "Claim your AIRDROP now", false},
+		{"substring inside word", []string{"ape"}, false, "grape juice", false},
+		{"no match", []string{"airdrop", "giveaway"}, false, "rates are rising", true},
+		{"any of several", []string{"airdrop", "giveaway"}, false, "huge Giveaway today", false},
+		{"term trimmed", []string{"  giveaway "}, false, "giveaway", false},
+		{"whole word", []string{"ape"}, true, "ape into this NFT", false},
+		{"whole word ignores case", []string{"ape"}, true, "just APE in", false},
+		{"whole word inside word", []string{"ape"}, true, "grape juice and apex", true},
+		{"whole word at end", []string{"ape"}, true, "time to ape", false},
+		{"whole word punctuation", []string{"ape"}, true, "ape, then ape!", false},
+		{"whole word underscore", []string{"ape"}, true, "ape_gang", true},
+		{"whole word unicode letters", []string{"café"}, true, "décaféiné", true},
+		{"whole word unicode match", []string{"café"}, true, "un café noir", false},
+		{"phrase", []string{"free money"}, true, "get FREE MONEY now", false},
+		{"metacharacters literal", []string{"$100"}, false, "win $100 today", false},
+		{"metacharacters not regex", []string{"a.c"}, false, "abc", true},
+		{"whole word metacharacters", []string{"c++"}, true, "learning c++ today", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExcludeTerms(tt.terms, tt.wholeWord)(SearchResult{Text: tt.text}); got != tt.want {
+				t.Errorf("ExcludeTerms(%q, %t) on %q kept = %t, want %t", tt.terms, tt.wholeWord, tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchExcludeTermsBeforeTruncation(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, `{"items":[
+			{"id":"1","text":"Airdrop live now"},
+			{"id":"2","text":"giveaway thread"},
+			{"id":"3","text":"rates held steady"},
+			{"id":"4","text":"markets rally"},
+			{"id":"5","text":"bonds slip"}
+		]}`)
+	})
+	resp, err := c.Search(context.Background(), "markets", 2, WithItemFilter(ExcludeTerms([]string{"airdrop", "giveaway"}, true)))
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if got := itemIDs(resp.Items); got != "3,4" {
+		t.Errorf("IDs = %s, want 3,4: excluded posts mustn't count towards max_results", got)
+	}
+}
@@ -0,0 +1,49 @@
+package masax
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// iso6391Codes are the two-letter ISO 639-1 language codes.
+var iso6391Codes = func() map[string]bool {
+	const codes = "aa ab ae af ak am an ar as av ay az ba be bg bh bi bm bn bo br bs ca ce ch co cr cs cu cv cy " +
+		"da de dv dz ee el en eo es et eu fa ff fi fj fo fr fy ga gd gl gn gu gv ha he hi ho hr ht hu hy hz " +
+		"ia id ie ig ii ik io is it iu ja jv ka kg ki kj kk kl km kn ko kr ks ku kv kw ky la lb lg li ln lo lt " +
+		"lu lv mg mh mi mk ml mn mr ms mt my na nb nd ne ng nl nn no nr nv ny oc oj om or os pa pi pl ps pt " +
+		"qu rm rn ro ru rw sa sc sd se sg si sk sl sm sn so sq sr ss st su sv sw ta te tg th ti tk tl tn to " +
+		"tr ts tt tw ty ug uk ur uz ve vi vo wa wo xh yi yo za zh zu"
+	set := make(map[string]bool)
+	for _, code := range strings.Fields(codes) {
+		set[code] = true
+	}
+	return set
+}()
+
+// LanguageCodes returns the ISO 639-1 codes ParseLanguage accepts, sorted.
+func LanguageCodes() []string {
+	codes := make([]string, 0, len(iso6391Codes))
+	for code := range iso6391Codes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// ParseLanguage validates and lower-cases an ISO 639-1 language code.
+func ParseLanguage(code string) (string, error) {
+	lang := strings.ToLower(strings.TrimSpace(code))
+	if !iso6391Codes[lang] {
+		return "", fmt.Errorf("unknown language code %q: expected a two-letter ISO 639-1 code such as \"en\"", code)
+	}
+	return lang, nil
+}
+
+// WithLanguage restricts results to posts in the given ISO 639-1 language.
+// The filter is applied server-side by the API via the lang request field.
+func WithLanguage(code string) SearchOption {
+	return func(o *searchOptions) {
+		o.lang = code
+	}
+}
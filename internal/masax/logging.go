@@ -0,0 +1,42 @@
+package masax
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+// sensitiveHeaders are replaced with a placeholder before headers are logged.
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// WithLogger enables structured debug logging of requests and responses.
+// Credentials are never logged. By default the client does not log.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// logDebug emits a debug record if a logger is configured, tagged with the
+// context's request ID.
+func (c *Client) logDebug(ctx context.Context, msg string, args ...any) {
+	if c.logger == nil {
+		return
+	}
+	if id := RequestIDFromContext(ctx); id != "" {
+		args = append(args, "request_id", id)
+	}
+	c.logger.DebugContext(ctx, msg, args...)
+}
+
+// redactHeaders returns a copy of h with sensitive values, including the API
+// key header, masked.
+func (c *Client) redactHeaders(h http.Header) http.Header {
+	redacted := h.Clone()
+	for _, name := range append(sensitiveHeaders, c.auth.headerName()) {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, "[REDACTED]")
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,87 @@
+package masax
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+)
+
+// ErrNotSupported is returned, wrapped, for operations the configured API
+// doesn't offer.
+var ErrNotSupported = errors.New("masa X: operation not supported")
+
+// TweetIDPattern is the regular expression X post IDs match: unsigned 64-bit
+// snowflakes.
+const TweetIDPattern = `^[0-9]{1,19}$`
+
+var tweetIDPattern = regexp.MustCompile(TweetIDPattern)
+
+// ValidateTweetID reports whether id looks like an X post ID.
+func ValidateTweetID(id string) error {
+	if !tweetIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid post ID %q: must be 1 to 19 digits", id)
+	}
+	return nil
+}
+
+// WithLookupPath sets the path, relative to the base URL, of an endpoint
+// that returns a single post: GetByID sends GET {base}/{path}/{id} and
+// expects one result object, shaped like SearchResponse.Items' elements.
+// The Masa X API documents no such endpoint, so none is configured by
+// default.
+func WithLookupPath(path string) ClientOption {
+	return func(c *Client) {
+		if path != "" {
+			c.lookupPath = path
+		}
+	}
+}
+
+// GetByID fetches a single post by ID. It returns an error wrapping
+// ErrNotSupported unless an endpoint has been configured with
+// WithLookupPath. Like Ping it makes a single attempt, bypassing the cache
+// and retries; the rate limit and circuit breaker apply.
+func (c *Client) GetByID(ctx context.Context, id string) (*SearchResult, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	if err := ValidateTweetID(id); err != nil {
+		return nil, err
+	}
+	if c.lookupPath == "" {
+		return nil, fmt.Errorf("%w: no post lookup endpoint is configured, and the Masa X API documents none", ErrNotSupported)
+	}
+	fullURL, err := url.JoinPath(c.apiBaseURL, c.lookupPath, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lookup URL: %w", err)
+	}
+
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	if err := c.waitForRateLimit(ctx); err != nil {
+		c.breaker.record(ctx, err)
+		return nil, err
+	}
+	body, header, _, err := c.do(ctx, http.MethodGet, fullURL, nil, nil, 0, c.timeout)
+	var retryErr *retryableError
+	if errors.As(err, &retryErr) {
+		err = retryErr.err
+	}
+	c.breaker.record(ctx, err)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, fmt.Errorf("post %s: %w", id, ErrEmptyResponse)
+	}
+	var result SearchResult
+	if err := c.decodeJSON(header, body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
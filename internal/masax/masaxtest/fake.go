@@ -0,0 +1,134 @@
+// Package masaxtest provides test doubles for code that depends on
+// masax.Searcher.
+package masaxtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"masax-mcp/internal/masax"
+)
+
+// Call records the arguments of one search made through a FakeSearcher.
+type Call struct {
+	Query      string
+	MaxResults int
+	NextToken  string
+}
+
+// FakeSearcher is an in-memory masax.Searcher returning canned responses.
+// Search options are accepted but ignored. It is safe for concurrent use;
+// configure its fields before use.
+type FakeSearcher struct {
+	// Responses maps a query to the response returned for it. Queries not
+	// present get Default, or an empty response if Default is nil.
+	Responses map[string]*masax.SearchResponse
+	Default   *masax.SearchResponse
+
+	// Errors maps a query to the error returned for it, taking precedence
+	// over Responses.
+	Errors map[string]error
+
+	// Posts maps an ID to the post GetByID returns for it. IDs not present
+	// get an error wrapping masax.ErrNotSupported.
+	Posts map[string]*masax.SearchResult
+
+	PingLatency time.Duration
+	PingErr     error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ masax.Searcher = (*FakeSearcher)(nil)
+
+// Calls returns the searches made so far, in order.
+func (f *FakeSearcher) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// Search returns the canned response or error for query, truncated to
+// maxResults items if it is positive.
+func (f *FakeSearcher) Search(ctx context.Context, query string, maxResults int, opts ...masax.SearchOption) (*masax.SearchResponse, error) {
+	return f.SearchPage(ctx, query, maxResults, "", opts...)
+}
+
+// SearchPage behaves like Search and records nextToken. The fake has a single
+// page per query.
+func (f *FakeSearcher) SearchPage(ctx context.Context, query string, maxResults int, nextToken string, _ ...masax.SearchOption) (*masax.SearchResponse, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, Call{Query: query, MaxResults: maxResults, NextToken: nextToken})
+	f.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := f.Errors[query]; err != nil {
+		return nil, err
+	}
+
+	resp, ok := f.Responses[query]
+	if !ok {
+		resp = f.Default
+	}
+	if resp == nil {
+		return &masax.SearchResponse{}, nil
+	}
+	cp := *resp
+	cp.Items = append([]masax.SearchResult(nil), resp.Items...)
+	if maxResults > 0 && len(cp.Items) > maxResults {
+		cp.Items = cp.Items[:maxResults]
+	}
+	return &cp, nil
+}
+
+// SearchAll behaves like Search with limit as maxResults.
+func (f *FakeSearcher) SearchAll(ctx context.Context, query string, limit int, opts ...masax.SearchOption) (*masax.SearchResponse, error) {
+	return f.Search(ctx, query, limit, opts...)
+}
+
+// SearchBatch runs Search for each query sequentially.
+func (f *FakeSearcher) SearchBatch(ctx context.Context, queries []string, maxResults int, _ int, opts ...masax.SearchOption) ([]*masax.SearchResponse, []error) {
+	results := make([]*masax.SearchResponse, len(queries))
+	errs := make([]error, len(queries))
+	for i, query := range queries {
+		results[i], errs[i] = f.Search(ctx, query, maxResults, opts...)
+	}
+	return results, errs
+}
+
+// SearchWindows runs Search for query once per window, sequentially. As
+// with other options, the windows' time ranges are ignored.
+func (f *FakeSearcher) SearchWindows(ctx context.Context, query string, windows []masax.TimeWindow, maxResults int, _ int, opts ...masax.SearchOption) ([]*masax.SearchResponse, []error) {
+	results := make([]*masax.SearchResponse, len(windows))
+	errs := make([]error, len(windows))
+	for i := range windows {
+		results[i], errs[i] = f.Search(ctx, query, maxResults, opts...)
+	}
+	return results, errs
+}
+
+// GetByID returns a copy of the post stored under id in Posts.
+func (f *FakeSearcher) GetByID(ctx context.Context, id string) (*masax.SearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	post, ok := f.Posts[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: no fake post with ID %q", masax.ErrNotSupported, id)
+	}
+	cp := *post
+	return &cp, nil
+}
+
+// Ping returns PingLatency and PingErr.
+func (f *FakeSearcher) Ping(ctx context.Context) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return f.PingLatency, f.PingErr
+}
@@ -0,0 +1,75 @@
+package masax
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// WithSearchMethod sets the HTTP method searches use: "POST", the default,
+// sends the SearchRequest as a JSON body; "GET" sends its fields as query
+// parameters of the same names, leaving out empty ones. The method is
+// case-insensitive, and empty keeps the default. NewClient returns an error
+// for any other method.
+func WithSearchMethod(method string) ClientOption {
+	return func(c *Client) {
+		method = strings.ToUpper(strings.TrimSpace(method))
+		switch method {
+		case "":
+		case http.MethodGet, http.MethodPost:
+			c.searchVerb = method
+		default:
+			c.setOptionErr(fmt.Errorf("unsupported search method %q: must be GET or POST", method))
+		}
+	}
+}
+
+// searchHTTPRequest returns the method, URL and body, nil for GET, of the
+// request for searchReq.
+func (c *Client) searchHTTPRequest(searchReq SearchRequest) (method, fullURL string, body []byte, err error) {
+	fullURL, err = c.searchURL()
+	if err != nil {
+		return "", "", nil, err
+	}
+	if c.searchVerb == http.MethodGet {
+		u, err := url.Parse(fullURL)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("failed to create search URL: %w", err)
+		}
+		q := u.Query() // Keep any parameters already in the base URL or path
+		for key, value := range searchReq.queryValues() {
+			q[key] = value
+		}
+		u.RawQuery = q.Encode()
+		return http.MethodGet, u.String(), nil, nil
+	}
+	body, err = json.Marshal(searchReq)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	return http.MethodPost, fullURL, body, nil
+}
+
+// queryValues encodes r as query parameters named like its JSON fields,
+// leaving out those the JSON body would omit.
+func (r SearchRequest) queryValues() url.Values {
+	q := url.Values{"query": {r.Query}}
+	if r.MaxResults != 0 {
+		q.Set("max_results", strconv.Itoa(r.MaxResults))
+	}
+	for key, value := range map[string]string{
+		"next_token": r.NextToken,
+		"start_time": r.StartTime,
+		"end_time":   r.EndTime,
+		"sort_by":    r.SortBy,
+		"lang":       r.Lang,
+	} {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	return q
+}
@@ -0,0 +1,156 @@
+package masax
+
+import (
+	"fmt"
+	"time"
+)
+
+// SearchOption configures a single search call.
+type SearchOption func(*searchOptions)
+
+// searchOptions holds the per-call settings applied by SearchOptions.
+type searchOptions struct {
+	timeout     time.Duration
+	bypassCache bool
+	startTime   time.Time
+	endTime     time.Time
+	sortBy      SortOrder
+	filters     []FilterFunc
+	lang        string
+	place       string
+	pointRadius *PointRadius
+	dedupe      Normalizer      // nil disables deduplication
+	sentiment   SentimentScorer // nil disables sentiment scoring
+	entities    bool
+	dryRun      bool
+	progress    func(Progress) // nil disables progress reports
+	ifNoneMatch string         // Set by fetch to revalidate a cached response
+
+	queryOperators []string // Appended to the query, e.g. -is:retweet
+
+	maxResultsLimit int // From the client; not settable per call
+	maxQueryLength  int // From the client; not settable per call
+
+	weights  EngagementWeights // From the client; not settable per call
+	synonyms Synonyms          // From the client; not settable per call
+
+	expansion Synonyms // nil disables query expansion
+}
+
+// WithRequestTimeout overrides the client's per-attempt timeout for one call.
+func WithRequestTimeout(d time.Duration) SearchOption {
+	return func(o *searchOptions) {
+		if d >= 0 {
+			o.timeout = d
+		}
+	}
+}
+
+// WithTimeRange restricts results to posts created between start and end. A
+// zero time leaves that side of the range open.
+func WithTimeRange(start, end time.Time) SearchOption {
+	return func(o *searchOptions) {
+		o.startTime = start
+		o.endTime = end
+	}
+}
+
+// newSearchOptions applies opts on top of the client's defaults.
+func (c *Client) newSearchOptions(opts []SearchOption) searchOptions {
+	o := searchOptions{
+		timeout:         c.timeout,
+		filters:         append([]FilterFunc(nil), c.filters...),
+		maxResultsLimit: c.maxResultsLimit,
+		maxQueryLength:  c.maxQueryLength,
+		weights:         c.engagementWeights,
+		synonyms:        c.synonyms,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// applyTo copies request parameters set through options onto searchReq.
+func (o searchOptions) applyTo(searchReq *SearchRequest) error {
+	query, err := NormalizeQuery(searchReq.Query, o.maxQueryLength)
+	if err != nil {
+		return err
+	}
+	if o.expansion != nil {
+		if err := o.expansion.Validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+		}
+		query = ExpandQuery(query, o.expansion)
+	}
+	geoOps, err := o.geoOperators()
+	if err != nil {
+		return err
+	}
+	searchReq.Query = appendOperators(query, append(append([]string(nil), o.queryOperators...), geoOps...))
+	if searchReq.MaxResults < 0 || searchReq.MaxResults > o.maxResultsLimit {
+		return fmt.Errorf("max results must be between 0 and %d, got %d", o.maxResultsLimit, searchReq.MaxResults)
+	}
+	if !o.startTime.IsZero() && !o.endTime.IsZero() && !o.startTime.Before(o.endTime) {
+		return fmt.Errorf("start time %s must be before end time %s",
+			o.startTime.Format(time.RFC3339), o.endTime.Format(time.RFC3339))
+	}
+	if !o.startTime.IsZero() {
+		searchReq.StartTime = o.startTime.UTC().Format(time.RFC3339)
+	}
+	if !o.endTime.IsZero() {
+		searchReq.EndTime = o.endTime.UTC().Format(time.RFC3339)
+	}
+	if _, err := ParseSortOrder(string(o.sortBy)); err != nil {
+		return err
+	}
+	if o.sortBy.serverSide() {
+		searchReq.SortBy = string(o.sortBy)
+	}
+	if o.lang != "" {
+		lang, err := ParseLanguage(o.lang)
+		if err != nil {
+			return err
+		}
+		searchReq.Lang = lang
+	}
+	return nil
+}
+
+// postProcess applies client-side filtering, deduplication, ordering and
+// enrichment to a fetched response.
+func (o searchOptions) postProcess(resp *SearchResponse) {
+	resp.Items = o.reduce(resp.Items)
+	o.order(resp.Items)
+	o.enrich(resp.Items)
+}
+
+// enrich adds the optional sentiment and entity fields to items.
+func (o searchOptions) enrich(items []SearchResult) {
+	o.scoreSentiment(items)
+	o.extractEntities(items)
+}
+
+// reducesResults reports whether client-side processing may drop results.
+func (o searchOptions) reducesResults() bool {
+	return len(o.filters) > 0 || o.dedupe != nil
+}
+
+// reduce applies filters and deduplication.
+func (o searchOptions) reduce(items []SearchResult) []SearchResult {
+	items = o.filter(items)
+	if o.dedupe != nil {
+		items = dedupeItems(items, o.dedupe, o.weights)
+	}
+	return items
+}
+
+// order applies client-side sort orders.
+func (o searchOptions) order(items []SearchResult) {
+	switch o.sortBy {
+	case SortEngagement:
+		sortByEngagement(items, o.weights)
+	case SortRecency:
+		sortByRecency(items)
+	}
+}
@@ -0,0 +1,104 @@
+package masax
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// maxNextTokenLength bounds the length of a pagination token accepted by
+// ValidateNextToken.
+const maxNextTokenLength = 512
+
+// nextTokenPattern matches the URL-safe and base64 characters used by
+// pagination tokens.
+var nextTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_\-.~+/=]+$`)
+
+// ValidateNextToken checks that token looks like a next_token from the
+// API's Metadata, so tokens from untrusted input can be passed to
+// SearchPage.
+func ValidateNextToken(token string) error {
+	if token == "" {
+		return fmt.Errorf("next_token is empty")
+	}
+	if len(token) > maxNextTokenLength {
+		return fmt.Errorf("next_token is %d characters long; maximum is %d", len(token), maxNextTokenLength)
+	}
+	if !nextTokenPattern.MatchString(token) {
+		return fmt.Errorf("next_token %q contains invalid characters", token)
+	}
+	return nil
+}
+
+// SearchPage fetches a single page of results. An empty nextToken requests the
+// first page; subsequent pages use the NextToken from the previous response's
+// Metadata.
+func (c *Client) SearchPage(ctx context.Context, query string, maxResults int, nextToken string, opts ...SearchOption) (*SearchResponse, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	return c.search(ctx, SearchRequest{
+		Query:      query,
+		MaxResults: maxResults,
+		NextToken:  nextToken,
+	}, c.newSearchOptions(opts))
+}
+
+// SearchAll follows next_token across pages until it has collected limit items
+// or the API reports no further pages. The returned response merges the Items
+// of every page and carries the Metadata of the last page fetched. It never
+// holds more than limit items: each page asks for at most the remainder, a
+// page returning more is truncated, and no page is fetched once limit is
+// reached.
+//
+// Client-side filters and deduplication apply to the merged items, so
+// duplicates on different pages are dropped, and a page they empty doesn't
+// end the search while the API has more. Sorting and enrichment run once on
+// the final result, so it's ordered as a whole.
+//
+// If a page fails after earlier pages succeeded, SearchAll returns the items
+// collected so far together with the error, so callers can use partial
+// results. The response is nil only if the first page fails.
+func (c *Client) SearchAll(ctx context.Context, query string, limit int, opts ...SearchOption) (*SearchResponse, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive, got %d", limit)
+	}
+
+	searchOpts := c.newSearchOptions(opts)
+	searchReq := SearchRequest{Query: query}
+	if err := searchOpts.applyTo(&searchReq); err != nil {
+		return nil, err
+	}
+	all := &SearchResponse{}
+	seenTokens := make(map[string]bool)
+	for page := 1; ; page++ {
+		searchReq.MaxResults = min(limit-len(all.Items), c.maxResultsLimit)
+		pageResp, err := c.fetch(ctx, searchReq, searchOpts)
+		if err != nil {
+			err = fmt.Errorf("failed to fetch page %d: %w", page, err)
+			if page == 1 {
+				return nil, err
+			}
+			return searchOpts.finishFiltered(all, limit), err
+		}
+		if len(pageResp.Items) > searchReq.MaxResults {
+			pageResp.Items = pageResp.Items[:searchReq.MaxResults] // Never more than asked for, as from Search
+		}
+		all.Items = searchOpts.reduce(append(all.Items, pageResp.Items...))
+		all.Metadata = pageResp.Metadata
+		all.Raw = pageResp.Raw
+		all.mergeFreshness(pageResp, page == 1)
+		searchOpts.reportProgress(Progress{Pages: page, Results: min(len(all.Items), limit), Target: limit})
+
+		// Stop once limit is reached, on the last page, on a page the API
+		// returned empty, or if the API hands back a token we've already
+		// followed, which would otherwise loop forever.
+		next := pageResp.Metadata.NextToken
+		if len(all.Items) >= limit || next == "" || len(pageResp.Items) == 0 || seenTokens[next] {
+			return searchOpts.finishFiltered(all, limit), nil
+		}
+		seenTokens[next] = true
+		searchReq.NextToken = next
+	}
+}
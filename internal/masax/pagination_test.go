@@ -0,0 +1,163 @@
+package masax
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pagedAPI is a fake search endpoint serving total items, numbered from 1,
+// over pages of at most pageSize items, or of exactly pageSize items
+// regardless of max_results if overshoot is set. Each page's next_token is
+// the offset of the following one.
+type pagedAPI struct {
+	total     int
+	pageSize  int
+	overshoot bool
+	failAt    int    // 1-based request that fails with a 500; 0 means none
+	fixedNext string // Returned as every next_token if set
+
+	mu        sync.Mutex
+	requested []int // max_results of each request
+}
+
+func (p *pagedAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req SearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	p.mu.Lock()
+	p.requested = append(p.requested, req.MaxResults)
+	n := len(p.requested)
+	p.mu.Unlock()
+	if n == p.failAt {
+		http.Error(w, "boom", http.StatusInternalServerError)
+		return
+	}
+
+	offset, _ := strconv.Atoi(req.NextToken)
+	size := p.pageSize
+	if !p.overshoot {
+		size = min(size, req.MaxResults)
+	}
+	end := min(offset+size, p.total)
+	resp := SearchResponse{Items: []SearchResult{}}
+	for i := offset; i < end; i++ {
+		resp.Items = append(resp.Items, SearchResult{ID: strconv.Itoa(i + 1), Text: fmt.Sprintf("post %d", i+1)})
+	}
+	if end < p.total {
+		resp.Metadata.NextToken = strconv.Itoa(end)
+	}
+	if p.fixedNext != "" {
+		resp.Metadata.NextToken = p.fixedNext
+	}
+	body, _ := json.Marshal(resp)
+	writeJSON(w, string(body))
+}
+
+func TestSearchAllLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		api           *pagedAPI
+		limit         int
+		opts          []ClientOption
+		wantItems     int
+		wantRequested []int
+	}{
+		{
+			name:          "page size doesn't divide limit",
+			api:           &pagedAPI{total: 100, pageSize: 3},
+			limit:         7,
+			wantItems:     7,
+			wantRequested: []int{7, 4, 1},
+		},
+		{
+			name:          "limit below page size",
+			api:           &pagedAPI{total: 100, pageSize: 10},
+			limit:         4,
+			wantItems:     4,
+			wantRequested: []int{4},
+		},
+		{
+			name:          "pages overshoot",
+			api:           &pagedAPI{total: 100, pageSize: 5, overshoot: true},
+			limit:         7,
+			wantItems:     7,
+			wantRequested: []int{7, 2},
+		},
+		{
+			name:          "pages overshoot small limit",
+			api:           &pagedAPI{total: 100, pageSize: 5, overshoot: true},
+			limit:         3,
+			wantItems:     3,
+			wantRequested: []int{3},
+		},
+		{
+			name:          "client max_results limit",
+			api:           &pagedAPI{total: 100, pageSize: 10},
+			limit:         5,
+			opts:          []ClientOption{WithMaxResultsLimit(2)},
+			wantItems:     5,
+			wantRequested: []int{2, 2, 1},
+		},
+		{
+			name:          "final short page",
+			api:           &pagedAPI{total: 8, pageSize: 3},
+			limit:         10,
+			wantItems:     8,
+			wantRequested: []int{10, 7, 4},
+		},
+		{
+			name:          "repeated next token",
+			api:           &pagedAPI{total: 100, pageSize: 2, fixedNext: "2"},
+			limit:         10,
+			wantItems:     4,
+			wantRequested: []int{10, 8},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, tt.api.ServeHTTP, tt.opts...)
+			resp, err := c.SearchAll(context.Background(), "paged", tt.limit)
+			if err != nil {
+				t.Fatalf("SearchAll: %v", err)
+			}
+			if len(resp.Items) != tt.wantItems {
+				t.Fatalf("got %d items, want %d", len(resp.Items), tt.wantItems)
+			}
+			for i, item := range resp.Items {
+				if want := strconv.Itoa(i + 1); item.ID != want {
+					t.Errorf("item %d ID = %s, want %s", i, item.ID, want)
+				}
+			}
+			if fmt.Sprint(tt.api.requested) != fmt.Sprint(tt.wantRequested) {
+				t.Errorf("requested max_results %v, want %v", tt.api.requested, tt.wantRequested)
+			}
+		})
+	}
+}
+
+func TestSearchAllPartialResults(t *testing.T) {
+	api := &pagedAPI{total: 100, pageSize: 3, failAt: 2}
+	c := newTestClient(t, api.ServeHTTP, WithRetry(1, time.Millisecond))
+
+	resp, err := c.SearchAll(context.Background(), "paged", 7)
+	if err == nil {
+		t.Fatal("SearchAll succeeded despite a failed page")
+	}
+	if resp == nil || len(resp.Items) != 3 {
+		t.Fatalf("SearchAll response = %+v, want the first page's 3 items", resp)
+	}
+
+	api = &pagedAPI{total: 100, pageSize: 3, failAt: 1}
+	c = newTestClient(t, api.ServeHTTP, WithRetry(1, time.Millisecond))
+	if resp, err := c.SearchAll(context.Background(), "paged", 7); err == nil || resp != nil {
+		t.Errorf("SearchAll with a failed first page = %+v, %v; want nil and an error", resp, err)
+	}
+}
@@ -0,0 +1,34 @@
+package masax
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// pingQuery is a cheap query used to verify connectivity and credentials.
+const pingQuery = "masa"
+
+// Ping issues a single minimal authenticated search, bypassing the cache and
+// retries, and returns the round-trip latency.
+func (c *Client) Ping(ctx context.Context) (time.Duration, error) {
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	method, fullURL, body, err := c.searchHTTPRequest(SearchRequest{Query: pingQuery, MaxResults: 1})
+	if err != nil {
+		return 0, err
+	}
+	if err := c.waitForRateLimit(ctx); err != nil {
+		return 0, err
+	}
+
+	start := c.now()
+	if _, _, err := c.send(ctx, method, fullURL, body, "", 0, c.timeout); err != nil {
+		var retryErr *retryableError
+		if errors.As(err, &retryErr) {
+			err = retryErr.err
+		}
+		return 0, err
+	}
+	return c.since(start), nil
+}
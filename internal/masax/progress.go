@@ -0,0 +1,25 @@
+package masax
+
+// Progress reports how far a multi-page search has got.
+type Progress struct {
+	Pages   int // Pages fetched so far
+	Results int // Results collected so far
+	Target  int // Results wanted
+}
+
+// WithProgress calls fn after each page fetched by SearchAll, or by Search
+// when client-side filters make it fetch several pages, so callers can
+// report progress on long searches. Single-page calls don't report
+// progress. fn is called synchronously from the search; keep it fast.
+func WithProgress(fn func(Progress)) SearchOption {
+	return func(o *searchOptions) {
+		o.progress = fn
+	}
+}
+
+// reportProgress calls the progress callback, if any.
+func (o searchOptions) reportProgress(p Progress) {
+	if o.progress != nil {
+		o.progress(p)
+	}
+}
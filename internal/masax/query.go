@@ -0,0 +1,45 @@
+package masax
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidQuery is returned, wrapped with the reason, for queries that are
+// empty or too long. Such queries are rejected before contacting the API.
+var ErrInvalidQuery = errors.New("masa X: invalid query")
+
+// quoteReplacer maps typographic quotes, which the API treats as ordinary
+// characters, to the ASCII quotes it uses for exact-phrase matching.
+var quoteReplacer = strings.NewReplacer(
+	"“", `"`, "”", `"`, "„", `"`, "‟", `"`, "″", `"`,
+	"‘", "'", "’", "'", "‚", "'", "‛", "'", "′", "'",
+)
+
+// NormalizeQuery trims query, collapses runs of whitespace (including
+// newlines) to single spaces and replaces smart quotes with ASCII ones. It
+// returns an error wrapping ErrInvalidQuery if nothing is left, or if
+// maxLength > 0 and the result is longer than maxLength characters.
+func NormalizeQuery(query string, maxLength int) (string, error) {
+	query = strings.Join(strings.Fields(quoteReplacer.Replace(query)), " ")
+	if query == "" {
+		return "", fmt.Errorf("%w: query is empty", ErrInvalidQuery)
+	}
+	if n := utf8.RuneCountInString(query); maxLength > 0 && n > maxLength {
+		return "", fmt.Errorf("%w: query is %d characters, the limit is %d", ErrInvalidQuery, n, maxLength)
+	}
+	return query, nil
+}
+
+// WithMaxQueryLength rejects queries longer than n characters, after
+// normalization and before any operators added by options, with
+// ErrInvalidQuery. By default query length isn't limited.
+func WithMaxQueryLength(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxQueryLength = n
+		}
+	}
+}
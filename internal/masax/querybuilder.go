@@ -0,0 +1,223 @@
+package masax
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// UsernamePattern is the regular expression the usernames accepted by
+// QueryBuilder.From match: an X handle of 1-15 letters, digits or
+// underscores, with an optional leading @.
+const UsernamePattern = `^@?[A-Za-z0-9_]{1,15}$`
+
+var (
+	// builderHandlePattern matches X handles: 1-15 letters, digits or underscores.
+	builderHandlePattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+	// builderHashtagPattern matches hashtag text: letters, digits and
+	// underscores, not all digits.
+	builderHashtagPattern = regexp.MustCompile(`^[\p{L}\p{N}_]*[\p{L}_][\p{L}\p{N}_]*$`)
+	// bareTermPattern matches keywords that need no quoting.
+	bareTermPattern = regexp.MustCompile(`^[\p{L}\p{N}_'.$&]+$`)
+)
+
+// QueryBuilder builds a query string from keywords and operators, quoting
+// and parenthesizing as needed. The parts of a builder are ANDed; use Or for
+// alternatives and Group to nest. The methods return the builder so calls
+// can be chained, and record the first invalid input for Build to report:
+//
+//	q, err := masax.NewQuery().
+//		Phrase("rate cut").
+//		Or(masax.NewQuery().From("federalreserve"), masax.NewQuery().Hashtag("FOMC")).
+//		Since(start).
+//		Build()
+//	// "rate cut" (from:federalreserve OR #FOMC) since:2024-05-01
+type QueryBuilder struct {
+	parts []string
+	err   error
+}
+
+// NewQuery returns an empty QueryBuilder.
+func NewQuery() *QueryBuilder {
+	return &QueryBuilder{}
+}
+
+// Raw adds query, already in query syntax, e.g. a user's search, as a single
+// part: parenthesized if it has several words, so its operators can't
+// combine with the parts around it.
+func (b *QueryBuilder) Raw(query string) *QueryBuilder {
+	words := strings.Fields(query)
+	switch len(words) {
+	case 0:
+		return b.fail(fmt.Errorf("empty query"))
+	case 1:
+		return b.add(words[0])
+	}
+	return b.add("(" + strings.Join(words, " ") + ")")
+}
+
+// Term adds a keyword, quoting it if it contains spaces, operator
+// characters or is a reserved word such as OR.
+func (b *QueryBuilder) Term(word string) *QueryBuilder {
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return b.fail(fmt.Errorf("empty term"))
+	}
+	if bareTermPattern.MatchString(word) && word != "OR" && word != "AND" {
+		return b.add(word)
+	}
+	return b.Phrase(word)
+}
+
+// Phrase adds an exact phrase, in double quotes. Phrases can't contain
+// double quotes themselves, as the API has no way to escape them.
+func (b *QueryBuilder) Phrase(phrase string) *QueryBuilder {
+	phrase = strings.Join(strings.Fields(phrase), " ")
+	if phrase == "" {
+		return b.fail(fmt.Errorf("empty phrase"))
+	}
+	if strings.ContainsAny(phrase, `"“”`) {
+		return b.fail(fmt.Errorf("phrase %q contains a double quote", phrase))
+	}
+	return b.add(`"` + phrase + `"`)
+}
+
+// From restricts results to posts by an account, given with or without the
+// leading @.
+func (b *QueryBuilder) From(username string) *QueryBuilder {
+	username = strings.TrimPrefix(strings.TrimSpace(username), "@")
+	if !builderHandlePattern.MatchString(username) {
+		return b.fail(fmt.Errorf("invalid username %q: must be 1-15 letters, digits or underscores", username))
+	}
+	return b.add("from:" + username)
+}
+
+// Hashtag matches posts with a hashtag, given with or without the leading #.
+func (b *QueryBuilder) Hashtag(tag string) *QueryBuilder {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	if !builderHashtagPattern.MatchString(tag) {
+		return b.fail(fmt.Errorf("invalid hashtag %q: must be letters, digits or underscores, not all digits", tag))
+	}
+	return b.add("#" + tag)
+}
+
+// Since restricts results to posts from t's UTC date onwards.
+func (b *QueryBuilder) Since(t time.Time) *QueryBuilder {
+	return b.add("since:" + t.UTC().Format(time.DateOnly))
+}
+
+// Until restricts results to posts before t's UTC date.
+func (b *QueryBuilder) Until(t time.Time) *QueryBuilder {
+	return b.add("until:" + t.UTC().Format(time.DateOnly))
+}
+
+// Between restricts results to the UTC dates from start up to end, leaving a
+// zero side open. The operators only have date granularity, so an end within
+// a day keeps that whole day; use WithTimeRange as well for exact bounds.
+func (b *QueryBuilder) Between(start, end time.Time) *QueryBuilder {
+	if !start.IsZero() {
+		b.Since(start)
+	}
+	if !end.IsZero() {
+		day := end.UTC().Truncate(24 * time.Hour)
+		if day.Before(end) {
+			day = day.Add(24 * time.Hour) // until: excludes its date
+		}
+		b.Until(day)
+	}
+	return b
+}
+
+// And adds each of qs as a group that must match. It is
+// equivalent to calling Group for each.
+func (b *QueryBuilder) And(qs ...*QueryBuilder) *QueryBuilder {
+	for _, q := range qs {
+		b.Group(q)
+	}
+	return b
+}
+
+// Group adds q as a single part, parenthesized if it has several.
+func (b *QueryBuilder) Group(q *QueryBuilder) *QueryBuilder {
+	expr, err := q.expr()
+	if err != nil {
+		return b.fail(err)
+	}
+	return b.add(expr)
+}
+
+// Or adds a part matching any of qs: (q1 OR q2 ...). A builder with several
+// parts counts as the AND of them.
+func (b *QueryBuilder) Or(qs ...*QueryBuilder) *QueryBuilder {
+	if len(qs) == 0 {
+		return b.fail(fmt.Errorf("empty OR"))
+	}
+	exprs := make([]string, 0, len(qs))
+	for _, q := range qs {
+		expr, err := q.expr()
+		if err != nil {
+			return b.fail(err)
+		}
+		exprs = append(exprs, expr)
+	}
+	if len(exprs) == 1 {
+		return b.add(exprs[0])
+	}
+	return b.add("(" + strings.Join(exprs, " OR ") + ")")
+}
+
+// Not excludes posts matching q: -term, or -(...) for several parts.
+func (b *QueryBuilder) Not(q *QueryBuilder) *QueryBuilder {
+	expr, err := q.expr()
+	if err != nil {
+		return b.fail(err)
+	}
+	return b.add("-" + expr)
+}
+
+// Build returns the query, or the first invalid input as an error wrapping
+// ErrInvalidQuery.
+func (b *QueryBuilder) Build() (string, error) {
+	if b.err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidQuery, b.err)
+	}
+	if len(b.parts) == 0 {
+		return "", fmt.Errorf("%w: query is empty", ErrInvalidQuery)
+	}
+	return strings.Join(b.parts, " "), nil
+}
+
+// String returns the query built so far, ignoring invalid inputs.
+func (b *QueryBuilder) String() string {
+	return strings.Join(b.parts, " ")
+}
+
+// expr renders b for nesting in another builder.
+func (b *QueryBuilder) expr() (string, error) {
+	if b == nil {
+		return "", fmt.Errorf("empty group")
+	}
+	if b.err != nil {
+		return "", b.err
+	}
+	if len(b.parts) == 0 {
+		return "", fmt.Errorf("empty group")
+	}
+	if len(b.parts) == 1 {
+		return b.parts[0], nil
+	}
+	return "(" + strings.Join(b.parts, " ") + ")", nil
+}
+
+func (b *QueryBuilder) add(part string) *QueryBuilder {
+	b.parts = append(b.parts, part)
+	return b
+}
+
+func (b *QueryBuilder) fail(err error) *QueryBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
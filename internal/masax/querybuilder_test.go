@@ -0,0 +1,152 @@
+package masax
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name  string
+		build *QueryBuilder
+		want  string
+	}{
+		{"single term", NewQuery().Term("bitcoin"), "bitcoin"},
+		{"terms are ANDed", NewQuery().Term("bitcoin").Term("etf"), "bitcoin etf"},
+		{"term with spaces is quoted", NewQuery().Term("rate cut"), `"rate cut"`},
+		{"reserved word is quoted", NewQuery().Term("OR"), `"OR"`},
+		{"operator characters are quoted", NewQuery().Term("from:alice"), `"from:alice"`},
+		{"phrase whitespace collapsed", NewQuery().Phrase("  rate \t cut "), `"rate cut"`},
+		{"from and hashtag prefixes", NewQuery().From("@alice").Hashtag("#FOMC"), "from:alice #FOMC"},
+		{"or", NewQuery().Or(NewQuery().Term("btc"), NewQuery().Term("eth")), "(btc OR eth)"},
+		{"or of one", NewQuery().Or(NewQuery().Term("btc")), "btc"},
+		{
+			"or of ands",
+			NewQuery().Or(NewQuery().Term("btc").Term("etf"), NewQuery().Hashtag("eth")),
+			"((btc etf) OR #eth)",
+		},
+		{
+			"and of ors",
+			NewQuery().And(
+				NewQuery().Or(NewQuery().Term("btc"), NewQuery().Term("eth")),
+				NewQuery().Or(NewQuery().From("alice"), NewQuery().From("bob")),
+			),
+			"(btc OR eth) (from:alice OR from:bob)",
+		},
+		{
+			"three levels",
+			NewQuery().Phrase("rate cut").Or(
+				NewQuery().From("federalreserve"),
+				NewQuery().Hashtag("FOMC").Group(NewQuery().Or(NewQuery().Term("powell"), NewQuery().Term("yellen"))),
+			),
+			`"rate cut" (from:federalreserve OR (#FOMC (powell OR yellen)))`,
+		},
+		{"group of one is bare", NewQuery().Group(NewQuery().Term("btc")), "btc"},
+		{"not term", NewQuery().Term("btc").Not(NewQuery().Term("scam")), "btc -scam"},
+		{"not group", NewQuery().Term("btc").Not(NewQuery().Term("scam").Term("giveaway")), "btc -(scam giveaway)"},
+		{"not or", NewQuery().Term("btc").Not(NewQuery().Or(NewQuery().Term("scam"), NewQuery().Term("airdrop"))), "btc -(scam OR airdrop)"},
+		{"raw single word", NewQuery().Raw(" btc "), "btc"},
+		{"raw is grouped", NewQuery().Raw("btc OR eth").From("alice"), "(btc OR eth) from:alice"},
+		{"since and until", NewQuery().Term("btc").Since(day(1)).Until(day(3)), "btc since:2024-05-01 until:2024-05-03"},
+		{"since uses UTC", NewQuery().Since(time.Date(2024, 5, 1, 23, 0, 0, 0, time.FixedZone("", -2*60*60))), "since:2024-05-02"},
+		{"between whole days", NewQuery().Between(day(1), day(3)), "since:2024-05-01 until:2024-05-03"},
+		{"between rounds end up", NewQuery().Between(day(1), day(3).Add(time.Minute)), "since:2024-05-01 until:2024-05-04"},
+		{"between open start", NewQuery().Between(time.Time{}, day(3)), "until:2024-05-03"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.build.Build()
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Build = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		build *QueryBuilder
+	}{
+		{"empty", NewQuery()},
+		{"empty term", NewQuery().Term("  ")},
+		{"phrase with quote", NewQuery().Phrase(`say "hi"`)},
+		{"long username", NewQuery().From("a_very_long_username")},
+		{"username with dash", NewQuery().From("bad-name")},
+		{"numeric hashtag", NewQuery().Hashtag("#2024")},
+		{"empty or", NewQuery().Or()},
+		{"empty group", NewQuery().Group(NewQuery())},
+		{"nil group", NewQuery().Group(nil)},
+		{"empty raw", NewQuery().Raw(" ")},
+		{"invalid nested in or", NewQuery().Term("btc").Or(NewQuery().Term("eth"), NewQuery().From("bad-name"))},
+		{"invalid nested two deep", NewQuery().Group(NewQuery().Term("x").Not(NewQuery().Hashtag("123")))},
+		{"first error kept", NewQuery().From("bad-name").Term("ok").Hashtag("123")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.build.Build()
+			if !errors.Is(err, ErrInvalidQuery) {
+				t.Fatalf("Build = %q, %v; want an error wrapping ErrInvalidQuery", got, err)
+			}
+		})
+	}
+
+	_, err := NewQuery().From("bad-name").Term("ok").Hashtag("123").Build()
+	if err == nil || !strings.Contains(err.Error(), `invalid username "bad-name"`) {
+		t.Errorf("Build error = %v, want the first invalid input reported", err)
+	}
+}
+
+func TestTimeWindowQuery(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		window TimeWindow
+		query  string
+		want   string
+	}{
+		{TimeWindow{}, "rate cut", "rate cut"},
+		{TimeWindow{Start: start, End: end}, "rate cut", "(rate cut) since:2024-01-01 until:2024-01-03"},
+		{TimeWindow{Start: start}, "btc", "btc since:2024-01-01"},
+		{TimeWindow{End: end}, "btc OR eth", "(btc OR eth) until:2024-01-03"},
+	}
+	for _, tt := range tests {
+		got, err := tt.window.Query(tt.query)
+		if err != nil {
+			t.Fatalf("Query(%q): %v", tt.query, err)
+		}
+		if got != tt.want {
+			t.Errorf("%+v.Query(%q) = %q, want %q", tt.window, tt.query, got, tt.want)
+		}
+	}
+	if _, err := (TimeWindow{Start: start}).Query(" "); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("Query of an empty query = %v, want ErrInvalidQuery", err)
+	}
+}
+
+func TestUsernamePattern(t *testing.T) {
+	pattern := regexp.MustCompile(UsernamePattern)
+	for _, name := range []string{"alice", "@alice", "A_1", "fifteen_chars_1"} {
+		if !pattern.MatchString(name) {
+			t.Errorf("UsernamePattern rejects %q", name)
+		}
+		if _, err := NewQuery().From(name).Build(); err != nil {
+			t.Errorf("From(%q): %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "@", "sixteen_chars_12", "bad-name", "@@alice", "alice bob"} {
+		if pattern.MatchString(name) {
+			t.Errorf("UsernamePattern accepts %q", name)
+		}
+		if _, err := NewQuery().From(name).Build(); err == nil {
+			t.Errorf("From(%q) succeeded, want an error", name)
+		}
+	}
+}
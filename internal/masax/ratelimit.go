@@ -0,0 +1,38 @@
+package masax
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimit throttles outgoing requests to rps requests per second with
+// bursts of up to burst requests. Each attempt, including retries, consumes a
+// token. By default the client is not rate limited.
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return func(c *Client) {
+		if rps <= 0 {
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		c.limiter = rate.NewLimiter(rate.Limit(rps), burst)
+	}
+}
+
+// waitForRateLimit blocks until the limiter permits another request. It
+// returns ctx.Err() if ctx is done first, or context.DeadlineExceeded if the
+// wait would outlast ctx's deadline.
+func (c *Client) waitForRateLimit(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+	if err := c.limiter.Wait(ctx); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return context.DeadlineExceeded
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package masax
+
+// WithRawResponse keeps the body of each search response, exactly as the
+// API sent it, in SearchResponse.Raw, for fields SearchResponse doesn't
+// model. Off by default, as it roughly doubles the memory a response takes,
+// including in the cache.
+func WithRawResponse() ClientOption {
+	return func(c *Client) {
+		c.captureRaw = true
+	}
+}
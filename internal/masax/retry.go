@@ -0,0 +1,183 @@
+package masax
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	maxRetryDelay         = 30 * time.Second
+)
+
+// WithRetry enables retrying transient failures (HTTP 429, 502, 503, 504 and
+// network errors) for up to maxAttempts total attempts. The delay between
+// attempts grows exponentially from baseDelay with added jitter, unless a 429
+// response carries a Retry-After header. Searches are assumed to be
+// idempotent, so safe to repeat; see WithNonIdempotentSearch.
+func WithRetry(maxAttempts int, baseDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		if maxAttempts > 0 {
+			c.maxAttempts = maxAttempts
+		}
+		if baseDelay > 0 {
+			c.baseDelay = baseDelay
+		}
+	}
+}
+
+// WithNonIdempotentSearch stops the client treating searches as safe to
+// repeat. Searches are sent as POST but only read data, so by default any
+// transient failure is retried, even one where the API may already have
+// processed the request. With this option, only failures where it cannot
+// have, a 429 rate limit or a connection that was never established, are
+// retried. Use it if the search endpoint may have side effects, such as
+// billing per request. Lookups and pings use GET and are always retried.
+func WithNonIdempotentSearch() ClientOption {
+	return func(c *Client) {
+		c.nonIdempotentSearch = true
+	}
+}
+
+// RetryStats counts retry activity since the client was created.
+type RetryStats struct {
+	// Retries is the number of attempts made after a transient failure.
+	Retries uint64 `json:"retries"`
+	// Exhausted is the number of searches that failed with a transient error
+	// because no attempts were left or the caller's deadline left no time
+	// to wait for another. With retries disabled, every transient failure
+	// counts.
+	Exhausted uint64 `json:"exhausted"`
+}
+
+// RetryStats returns the client's cumulative retry counts. A rising
+// Retries count with few Exhausted searches means the API is flaky but
+// retries are absorbing it.
+func (c *Client) RetryStats() RetryStats {
+	return RetryStats{
+		Retries:   c.retryCounts.retries.Load(),
+		Exhausted: c.retryCounts.exhausted.Load(),
+	}
+}
+
+// retryCounters backs RetryStats.
+type retryCounters struct {
+	retries   atomic.Uint64
+	exhausted atomic.Uint64
+}
+
+// WithRetryBudget bounds retrying. No backoff sleep exceeds maxDelay (30s by
+// default), and a search gives up once its attempts and sleeps would take
+// more than maxTotal in all: no sleep is started that would end past the
+// budget, and each attempt's timeout is cut to what is left of it. A 429
+// whose Retry-After exceeds maxDelay isn't retried, rather than retried
+// early. A context deadline shorter than the budget still applies. A zero
+// maxTotal leaves the total unbounded, and a zero maxDelay keeps the
+// default cap.
+func WithRetryBudget(maxTotal, maxDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		if maxTotal >= 0 {
+			c.retryMaxTotal = maxTotal
+		}
+		if maxDelay > 0 {
+			c.retryMaxDelay = maxDelay
+		}
+	}
+}
+
+// retryBudgetLeft returns how much of the retry budget is left for a search
+// whose first attempt started at start, and false if there is no budget.
+func (c *Client) retryBudgetLeft(start time.Time) (time.Duration, bool) {
+	if c.retryMaxTotal <= 0 {
+		return 0, false
+	}
+	return c.retryMaxTotal - c.since(start), true
+}
+
+// retryableError marks a failure that may succeed if the request is repeated.
+type retryableError struct {
+	err        error
+	retryAfter time.Duration // Server-requested delay, if any
+	unsent     bool          // The API cannot have processed the request
+}
+
+// safeToRetry reports whether a search that failed with e may be repeated
+// given the client's idempotency assumption.
+func (c *Client) safeToRetry(e *retryableError) bool {
+	return !c.nonIdempotentSearch || e.unsent
+}
+
+// isDialError reports whether err happened while connecting, before any of
+// the request was sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+
+func (e *retryableError) Unwrap() error { return e.err }
+
+// isRetryableStatus reports whether an HTTP status indicates a transient failure.
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay before the retry following the given zero-based
+// attempt: baseDelay*2^attempt, capped at the maximum delay, with the upper half
+// randomized to spread out concurrent retries.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.baseDelay
+	for range attempt {
+		if d > c.retryMaxDelay/2 {
+			d = c.retryMaxDelay // Capped before doubling, so it can't overflow
+			break
+		}
+		d *= 2
+	}
+	d = min(d, c.retryMaxDelay)
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an
+// HTTP date, measured from now. It returns 0 if the header is absent or
+// invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// sleep waits on the client's clock for d or until ctx is done, whichever
+// comes first.
+func (c *Client) sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.clock.After(d):
+		return nil
+	}
+}
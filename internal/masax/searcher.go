@@ -0,0 +1,21 @@
+package masax
+
+import (
+	"context"
+	"time"
+)
+
+// Searcher is the set of search operations offered by Client. Depend on it
+// rather than *Client so tests can substitute a fake such as
+// masaxtest.FakeSearcher.
+type Searcher interface {
+	Search(ctx context.Context, query string, maxResults int, opts ...SearchOption) (*SearchResponse, error)
+	SearchPage(ctx context.Context, query string, maxResults int, nextToken string, opts ...SearchOption) (*SearchResponse, error)
+	SearchAll(ctx context.Context, query string, limit int, opts ...SearchOption) (*SearchResponse, error)
+	SearchBatch(ctx context.Context, queries []string, maxResults int, concurrency int, opts ...SearchOption) ([]*SearchResponse, []error)
+	SearchWindows(ctx context.Context, query string, windows []TimeWindow, maxResults int, concurrency int, opts ...SearchOption) ([]*SearchResponse, []error)
+	GetByID(ctx context.Context, id string) (*SearchResult, error)
+	Ping(ctx context.Context) (time.Duration, error)
+}
+
+var _ Searcher = (*Client)(nil)
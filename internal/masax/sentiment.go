@@ -0,0 +1,109 @@
+package masax
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Sentiment labels returned by LexiconScorer.
+const (
+	SentimentPositive = "positive"
+	SentimentNegative = "negative"
+	SentimentNeutral  = "neutral"
+)
+
+// Sentiment is the mood attached to a result by a SentimentScorer.
+type Sentiment struct {
+	Label string  `json:"label"`
+	Score float64 `json:"score"`
+}
+
+// SentimentScorer labels a post's text. Score's range is up to the
+// implementation; LexiconScorer uses -1 (negative) to 1 (positive).
+type SentimentScorer interface {
+	Score(text string) (label string, score float64, err error)
+}
+
+// WithSentiment scores each result with scorer and sets its Sentiment. A nil
+// scorer uses LexiconScorer. Results the scorer fails on are left unscored.
+func WithSentiment(scorer SentimentScorer) SearchOption {
+	return func(o *searchOptions) {
+		if scorer == nil {
+			scorer = LexiconScorer{}
+		}
+		o.sentiment = scorer
+	}
+}
+
+// scoreSentiment sets Sentiment on items when a scorer is configured.
+func (o searchOptions) scoreSentiment(items []SearchResult) {
+	if o.sentiment == nil {
+		return
+	}
+	for i := range items {
+		label, score, err := o.sentiment.Score(items[i].Text)
+		if err != nil {
+			continue
+		}
+		items[i].Sentiment = &Sentiment{Label: label, Score: score}
+	}
+}
+
+// LexiconScorer is a small English word-list scorer. Its score is the
+// balance of positive and negative words among those it recognizes, with a
+// preceding "not", "no" or "never" flipping a word's polarity. It never
+// returns an error.
+type LexiconScorer struct{}
+
+var (
+	positiveWords = wordSet("good great love loved loves awesome amazing excellent nice happy glad best better win wins winning won cool fantastic wonderful like likes liked enjoy enjoyed fun beautiful perfect bullish thanks thank impressive brilliant exciting excited success successful strong up gain gains profit")
+	negativeWords = wordSet("bad terrible hate hated hates awful horrible worst worse lose loses losing lost sad angry poor fail fails failed failure broken bug bugs scam ugly boring disappointing disappointed bearish crash crashed down weak problem problems issue issues wrong annoying slow loss losses")
+	negationWords = wordSet("not no never don't doesn't didn't isn't wasn't can't won't")
+)
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+// Score implements SentimentScorer.
+func (LexiconScorer) Score(text string) (string, float64, error) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	var positive, negative int
+	for i, w := range words {
+		polarity := 0
+		switch {
+		case positiveWords[w]:
+			polarity = 1
+		case negativeWords[w]:
+			polarity = -1
+		default:
+			continue
+		}
+		if i > 0 && negationWords[words[i-1]] {
+			polarity = -polarity
+		}
+		if polarity > 0 {
+			positive++
+		} else {
+			negative++
+		}
+	}
+	if positive+negative == 0 {
+		return SentimentNeutral, 0, nil
+	}
+	score := float64(positive-negative) / float64(positive+negative)
+	switch {
+	case score > 0:
+		return SentimentPositive, score, nil
+	case score < 0:
+		return SentimentNegative, score, nil
+	default:
+		return SentimentNeutral, 0, nil
+	}
+}
@@ -0,0 +1,98 @@
+package masax
+
+import (
+	"context"
+	"fmt"
+)
+
+// sharedFetch tracks the callers waiting on an in-flight shared request.
+type sharedFetch struct {
+	ctx     context.Context    // Context the request runs on
+	cancel  context.CancelFunc // Abandons the request once no caller waits
+	waiters int                // Callers still waiting, guarded by Client.sharedMu
+}
+
+// fetchShared runs fetchUncached for searchReq, sharing the call with any
+// concurrent identical request through singleflight. Requests are identical
+// if they have the same cache key, i.e. the same normalized query,
+// max_results and other request fields, and the same per-attempt timeout
+// and cache use, so a caller never inherits options it didn't ask for.
+//
+// A caller whose context has a deadline of its own fetches alone, so retry
+// decisions such as giving up on a Retry-After that would outlast the
+// deadline are made against that deadline. Other callers share a call that
+// runs detached from their contexts, keeping only their values such as the
+// request ID, and bounded by the client's default deadline if there is one,
+// which no waiter's deadline precedes. A caller whose context ends stops
+// waiting without affecting the others; once every caller has stopped, the
+// call is cancelled and forgotten, so the next identical search starts a new
+// one. Every caller gets its own copy of the response, since
+// post-processing modifies it.
+func (c *Client) fetchShared(ctx context.Context, searchReq SearchRequest, opts searchOptions, useCache bool) (*SearchResponse, error) {
+	if _, ok := ctx.Deadline(); ok && ctx.Value(defaultDeadlineKey{}) == nil {
+		return c.fetchUncached(ctx, searchReq, opts, useCache)
+	}
+	key := fmt.Sprintf("%s\x00timeout=%s\x00cache=%t", cacheKey(searchReq), opts.timeout, useCache)
+
+	c.sharedMu.Lock()
+	if c.shared == nil {
+		c.shared = make(map[string]*sharedFetch)
+	}
+	call, ok := c.shared[key]
+	if !ok {
+		call = &sharedFetch{}
+		call.ctx, call.cancel = c.sharedContext(ctx)
+		c.shared[key] = call
+	}
+	call.waiters++
+	ch := c.inflight.DoChan(key, func() (interface{}, error) {
+		defer call.cancel()
+		resp, err := c.fetchUncached(call.ctx, searchReq, opts, useCache)
+		c.sharedMu.Lock()
+		if c.shared[key] == call {
+			delete(c.shared, key)
+		}
+		c.sharedMu.Unlock()
+		return resp, err
+	})
+	c.sharedMu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		c.leaveShared(key, call)
+		return nil, ctx.Err()
+	case res := <-ch:
+		c.leaveShared(key, call)
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return copyResponse(res.Val.(*SearchResponse)), nil
+	}
+}
+
+// sharedContext returns the context a shared call runs on: detached from
+// ctx, but bounded by the default deadline if the client has one.
+func (c *Client) sharedContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx = context.WithoutCancel(ctx)
+	if c.defaultDeadline > 0 {
+		return context.WithTimeout(ctx, c.defaultDeadline)
+	}
+	return context.WithCancel(ctx)
+}
+
+// leaveShared records that a caller stopped waiting for call, cancelling it
+// if it was the last. A call abandoned before it finished is forgotten at
+// once, so later searches never join it.
+func (c *Client) leaveShared(key string, call *sharedFetch) {
+	c.sharedMu.Lock()
+	defer c.sharedMu.Unlock()
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+	call.cancel()
+	if c.shared[key] == call {
+		delete(c.shared, key)
+		c.inflight.Forget(key)
+	}
+}
@@ -0,0 +1,101 @@
+package masax
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSearchSharesIdenticalRequests(t *testing.T) {
+	release := make(chan struct{})
+	var requests atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		requests.Add(1)
+		<-release
+		writeJSON(w, `{"items":[{"id":"1","text":"hello"}]}`)
+	})
+
+	const callers = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.Search(context.Background(), "shared", 10)
+			if err == nil && len(resp.Items) != 1 {
+				err = errors.New("missing items")
+			}
+			errs <- err
+		}()
+	}
+	waitFor(t, "the shared request", func() bool { return requests.Load() == 1 })
+	waitFor(t, "every caller to join", func() bool {
+		c.sharedMu.Lock()
+		defer c.sharedMu.Unlock()
+		for _, call := range c.shared {
+			return call.waiters == callers
+		}
+		return false
+	})
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Search: %v", err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("API requests = %d, want 1", got)
+	}
+}
+
+func TestSearchRetryAfterBeyondCallerDeadline(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ClientOption
+		ctx  func() (context.Context, context.CancelFunc)
+	}{
+		{
+			name: "caller deadline",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 2*time.Second)
+			},
+		},
+		{
+			name: "default deadline",
+			opts: []ClientOption{WithDefaultDeadline(2 * time.Second)},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.Background(), func() {}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, requests := flakyHandler(1, http.StatusTooManyRequests, map[string]string{"Retry-After": "10"})
+			c := newTestClient(t, handler, append([]ClientOption{WithRetry(3, 10*time.Millisecond)}, tt.opts...)...)
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			start := time.Now()
+			_, err := c.Search(ctx, "limited", 10)
+			if !errors.Is(err, ErrRateLimited) {
+				t.Fatalf("Search error = %v, want ErrRateLimited", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Search took %v, want the 429 returned without waiting", elapsed)
+			}
+			if got := requests.Load(); got != 1 {
+				t.Errorf("API requests = %d, want 1", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package masax
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortOrder selects how search results are ordered.
+type SortOrder string
+
+// Supported sort orders. SortRecency and SortRelevancy are applied by the API;
+// SortEngagement is applied client-side after results are fetched, so it only
+// reorders the items within the returned page, weighing metrics as set by
+// WithEngagementWeights. Client-side sorts break ties by descending post ID,
+// and SortRecency results are re-sorted the same way, so repeated searches
+// over the same posts return them in the same order.
+const (
+	SortRecency    SortOrder = "recency"
+	SortRelevancy  SortOrder = "relevancy"
+	SortEngagement SortOrder = "engagement"
+)
+
+// SortOrders lists the valid SortOrder values.
+var SortOrders = []SortOrder{SortRecency, SortRelevancy, SortEngagement}
+
+// ParseSortOrder validates a sort order name. An empty name yields the empty
+// SortOrder, which keeps the API's default ordering.
+func ParseSortOrder(name string) (SortOrder, error) {
+	if name == "" {
+		return "", nil
+	}
+	for _, order := range SortOrders {
+		if SortOrder(name) == order {
+			return order, nil
+		}
+	}
+	return "", fmt.Errorf("unknown sort order %q (valid: %v)", name, SortOrders)
+}
+
+// serverSide reports whether the API applies this ordering itself.
+func (o SortOrder) serverSide() bool {
+	return o == SortRecency || o == SortRelevancy
+}
+
+// WithSortBy orders results by the given SortOrder. By default results keep
+// the API's ordering.
+func WithSortBy(order SortOrder) SearchOption {
+	return func(o *searchOptions) {
+		o.sortBy = order
+	}
+}
+
+// EngagementScore sums a result's likes, retweets, replies and quotes.
+func EngagementScore(m PublicMetrics) int {
+	return m.LikeCount + m.RetweetCount + m.ReplyCount + m.QuoteCount
+}
+
+// sortByEngagement orders items by descending weighted engagement score,
+// then by descending ID.
+func sortByEngagement(items []SearchResult, w EngagementWeights) {
+	sort.SliceStable(items, func(i, j int) bool {
+		si, sj := w.Score(items[i].PublicMetrics), w.Score(items[j].PublicMetrics)
+		if si != sj {
+			return si > sj
+		}
+		return compareIDs(items[i].ID, items[j].ID) > 0
+	})
+}
+
+// sortByRecency orders items by descending CreatedAt, then by descending ID.
+func sortByRecency(items []SearchResult) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].CreatedAt.Equal(items[j].CreatedAt) {
+			return items[i].CreatedAt.After(items[j].CreatedAt)
+		}
+		return compareIDs(items[i].ID, items[j].ID) > 0
+	})
+}
+
+// compareIDs orders post IDs numerically, returning -1, 0 or +1. Post IDs
+// are decimal without leading zeros, so a longer ID is larger; IDs of equal
+// length compare as strings, which also gives any other IDs a fixed order.
+func compareIDs(a, b string) int {
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,91 @@
+package masax
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCompareIDs(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1", "1", 0},
+		{"9", "10", -1},
+		{"1789000000000000001", "1789000000000000000", 1},
+		{"100", "99", 1},
+		{"abc", "abd", -1},
+		{"", "1", -1},
+	}
+	for _, tt := range tests {
+		if got := compareIDs(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareIDs(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := compareIDs(tt.b, tt.a); got != -tt.want {
+			t.Errorf("compareIDs(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestSortsAreDeterministicOnTies(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	items := []SearchResult{
+		{ID: "9", CreatedAt: at, PublicMetrics: PublicMetrics{LikeCount: 5}},
+		{ID: "10", CreatedAt: at, PublicMetrics: PublicMetrics{RetweetCount: 5}},
+		{ID: "11", CreatedAt: at.Add(-time.Hour), PublicMetrics: PublicMetrics{LikeCount: 2, ReplyCount: 3}},
+		{ID: "8", CreatedAt: at.Add(time.Hour), PublicMetrics: PublicMetrics{LikeCount: 1}},
+		{ID: "100", CreatedAt: at, PublicMetrics: PublicMetrics{LikeCount: 1}},
+	}
+	tests := []struct {
+		name string
+		sort func([]SearchResult)
+		want string
+	}{
+		// 11, 10 and 9 score 5; 100 and 8 score 1
+		{"engagement", func(items []SearchResult) { sortByEngagement(items, DefaultEngagementWeights) }, "11,10,9,100,8"},
+		// Weighting likes breaks the ties between 11, 10 and 9
+		{"weighted engagement", func(items []SearchResult) {
+			sortByEngagement(items, EngagementWeights{Likes: 2, Retweets: 1, Replies: 1, Quotes: 1})
+		}, "9,11,10,100,8"},
+		{"recency", sortByRecency, "8,100,10,9,11"},
+	}
+	rng := rand.New(rand.NewSource(1))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				shuffled := append([]SearchResult(nil), items...)
+				rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+				tt.sort(shuffled)
+				if got := itemIDs(shuffled); got != tt.want {
+					t.Fatalf("sorted %s, want %s", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchSortIsReproducible(t *testing.T) {
+	// The API returns the same tied posts in a different order each time.
+	pages := []string{
+		`{"items":[{"id":"1","public_metrics":{"like_count":3}},{"id":"2","public_metrics":{"like_count":3}},{"id":"3","public_metrics":{"like_count":7}}]}`,
+		`{"items":[{"id":"3","public_metrics":{"like_count":7}},{"id":"2","public_metrics":{"like_count":3}},{"id":"1","public_metrics":{"like_count":3}}]}`,
+		`{"items":[{"id":"2","public_metrics":{"like_count":3}},{"id":"3","public_metrics":{"like_count":7}},{"id":"1","public_metrics":{"like_count":3}}]}`,
+	}
+	var call atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, pages[int(call.Add(1)-1)%len(pages)])
+	})
+	for i := range pages {
+		resp, err := c.Search(context.Background(), "ties", 10, WithSortBy(SortEngagement))
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		if got := itemIDs(resp.Items); got != "3,2,1" {
+			t.Errorf("search %d returned %s, want 3,2,1", i+1, got)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package masax
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Synonyms maps query terms to alternatives for query expansion, e.g.
+// {"AI": {"artificial intelligence", "machine learning"}}. Terms match
+// ignoring case and surrounding whitespace; a multi-word term matches the
+// same exact phrase in double quotes.
+type Synonyms map[string][]string
+
+// ParseSynonyms parses synonyms written as term=alternative|alternative,
+// with entries separated by semicolons, e.g.
+// "AI=artificial intelligence|machine learning;BTC=bitcoin". An empty
+// string yields no synonyms.
+func ParseSynonyms(s string) (Synonyms, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	syn := make(Synonyms)
+	for _, entry := range strings.Split(s, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		term, alts, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid synonym entry %q: expected term=alternative|alternative", entry)
+		}
+		term = strings.TrimSpace(term)
+		for _, alt := range strings.Split(alts, "|") {
+			if alt = strings.TrimSpace(alt); alt != "" {
+				syn[term] = append(syn[term], alt)
+			}
+		}
+	}
+	return syn, syn.Validate()
+}
+
+// Validate reports an empty term, a term without alternatives, or a term or
+// alternative containing a double quote, which can't be expressed in a query.
+func (syn Synonyms) Validate() error {
+	for term, alts := range syn {
+		if strings.TrimSpace(term) == "" {
+			return fmt.Errorf("invalid synonyms: empty term")
+		}
+		if strings.ContainsAny(term, `"“”`) {
+			return fmt.Errorf("invalid synonyms: term %q contains a double quote", term)
+		}
+		if len(alts) == 0 {
+			return fmt.Errorf("invalid synonyms: term %q has no alternatives", term)
+		}
+		for _, alt := range alts {
+			if _, err := NewQuery().Term(alt).Build(); err != nil {
+				return fmt.Errorf("invalid synonyms for %q: %v", term, err)
+			}
+		}
+	}
+	return nil
+}
+
+// merge returns the union of syn and other, with other's alternatives added
+// after syn's for terms in both.
+func (syn Synonyms) merge(other Synonyms) Synonyms {
+	merged := make(Synonyms, len(syn)+len(other))
+	for _, s := range []Synonyms{syn, other} {
+		for term, alts := range s {
+			key := synonymKey(term)
+			merged[key] = append(merged[key], alts...)
+		}
+	}
+	return merged
+}
+
+// lookup returns the alternatives for a query token, a bare term or a quoted
+// phrase, leaving out any equal to the token itself.
+func (syn Synonyms) lookup(token string) []string {
+	key := synonymKey(strings.Trim(token, `"`))
+	var terms []string
+	for term := range syn {
+		if synonymKey(term) == key {
+			terms = append(terms, term)
+		}
+	}
+	sort.Strings(terms) // Map order is random; keep expanded queries stable
+	var alts []string
+	for _, term := range terms {
+		for _, alt := range syn[term] {
+			if synonymKey(alt) != key && !containsFold(alts, alt) {
+				alts = append(alts, alt)
+			}
+		}
+	}
+	return alts
+}
+
+func synonymKey(term string) string {
+	return strings.ToLower(strings.Join(strings.Fields(term), " "))
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithSynonyms sets the synonyms searches expand their queries with when
+// they opt in with WithQueryExpansion. NewClient returns an error for
+// invalid synonyms.
+func WithSynonyms(syn Synonyms) ClientOption {
+	return func(c *Client) {
+		if err := syn.Validate(); err != nil {
+			c.setOptionErr(err)
+			return
+		}
+		c.synonyms = c.synonyms.merge(syn)
+	}
+}
+
+// WithQueryExpansion expands the query for better recall: each term or
+// quoted phrase with synonyms, from the client's WithSynonyms and extra,
+// is replaced by an OR of it and its alternatives, so "AI news" becomes
+// (AI OR "artificial intelligence") news. Negated terms and operators such
+// as from: are left alone. The expanded query isn't subject to
+// WithMaxQueryLength. Invalid extra synonyms fail the search.
+func WithQueryExpansion(extra Synonyms) SearchOption {
+	return func(o *searchOptions) {
+		o.expansion = o.synonyms.merge(extra)
+	}
+}
+
+// ExpandQuery returns query with each term or quoted phrase that has
+// synonyms replaced by an OR of it and its alternatives. Leading and
+// trailing parentheses around a token are kept in place.
+func ExpandQuery(query string, syn Synonyms) string {
+	if len(syn) == 0 {
+		return query
+	}
+	tokens := splitQueryTokens(query)
+	for i, token := range tokens {
+		core := strings.TrimLeft(token, "(")
+		open := token[:len(token)-len(core)]
+		trimmed := strings.TrimRight(core, ")")
+		closing := core[len(trimmed):]
+		if trimmed == "" || trimmed == "OR" || trimmed == "AND" {
+			continue
+		}
+		alts := syn.lookup(trimmed)
+		if len(alts) == 0 {
+			continue
+		}
+		parts := []string{trimmed}
+		for _, alt := range alts {
+			parts = append(parts, NewQuery().Term(alt).String())
+		}
+		tokens[i] = open + "(" + strings.Join(parts, " OR ") + ")" + closing
+	}
+	return strings.Join(tokens, " ")
+}
+
+// splitQueryTokens splits query at whitespace outside double quotes, so a
+// quoted phrase stays one token.
+func splitQueryTokens(query string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
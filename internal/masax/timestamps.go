@@ -0,0 +1,115 @@
+package masax
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timestampLayouts are the created_at formats accepted, tried in order.
+// Layouts without a UTC offset are taken to be in UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999", // RFC 3339 without an offset
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	time.RubyDate, // Legacy X API: "Wed Oct 10 20:19:24 +0000 2018"
+}
+
+// ParseTimestamp parses a post timestamp in any of the formats the API has
+// been seen to use: RFC 3339 with or without fractional seconds or an
+// offset, the same with a space instead of the T, or the legacy X format.
+// Timestamps without an offset are taken to be in UTC. The result is always
+// in UTC.
+func ParseTimestamp(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", s)
+}
+
+// UnmarshalJSON decodes a search result, parsing created_at with
+// ParseTimestamp. created_at may also be Unix seconds or milliseconds; null
+// or an empty string leaves it zero. Author verification is also taken from
+// a nested author object, as in the X API's user expansion.
+func (r *SearchResult) UnmarshalJSON(data []byte) error {
+	type plain SearchResult // Without this method, to avoid recursion
+	aux := struct {
+		*plain
+		CreatedAt json.RawMessage `json:"created_at"`
+		Author    *struct {
+			Verified *bool `json:"verified"`
+		} `json:"author"`
+	}{plain: (*plain)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if r.AuthorVerified == nil && aux.Author != nil {
+		r.AuthorVerified = aux.Author.Verified
+	}
+	createdAt, err := parseCreatedAt(aux.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("invalid created_at for post %q: %w", r.ID, err)
+	}
+	r.CreatedAt = createdAt
+	return nil
+}
+
+// parseCreatedAt decodes a created_at JSON value to a UTC time.
+func parseCreatedAt(raw json.RawMessage) (time.Time, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return time.Time{}, nil
+	}
+	if raw[0] != '"' {
+		n, err := strconv.ParseInt(string(raw), 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unrecognized timestamp %s", raw)
+		}
+		if n > 1e11 { // Too large for seconds (year 5138), so milliseconds
+			return time.UnixMilli(n).UTC(), nil
+		}
+		return time.Unix(n, 0).UTC(), nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return time.Time{}, err
+	}
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return ParseTimestamp(s)
+}
+
+// LoadTimezone resolves an IANA time zone name such as "America/New_York",
+// or "UTC" (also used for an empty name), for display. It rejects "Local",
+// which would depend on where the server runs.
+func LoadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "UTC") {
+		return time.UTC, nil
+	}
+	if name == "Local" {
+		return nil, fmt.Errorf("invalid time zone %q: use an IANA name such as \"Europe/London\"", name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q: use an IANA name such as \"Europe/London\"", name)
+	}
+	return loc, nil
+}
+
+// FormatTimestamp formats t in loc for display, e.g.
+// "2024-05-01 09:30 EDT". A nil loc means UTC.
+func FormatTimestamp(t time.Time, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format("2006-01-02 15:04 MST")
+}
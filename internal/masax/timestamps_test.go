@@ -0,0 +1,113 @@
+package masax
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	_ "time/tzdata" // LoadTimezone tests don't depend on the host's zone files
+)
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2024, 5, 1, 13, 30, 15, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2024-05-01T13:30:15Z", want},
+		{"2024-05-01T13:30:15.000Z", want},
+		{"2024-05-01T13:30:15.123456789Z", want.Add(123456789)},
+		{"2024-05-01T15:30:15+02:00", want},
+		{"2024-05-01T09:30:15-04:00", want},
+		{"2024-05-01T13:30:15", want},
+		{"2024-05-01T13:30:15.5", want.Add(500 * time.Millisecond)},
+		{"2024-05-01 13:30:15Z", want},
+		{"2024-05-01 15:30:15+02:00", want},
+		{"2024-05-01 13:30:15", want},
+		{"Wed May 01 13:30:15 +0000 2024", want},
+		{"Wed May 01 09:30:15 -0400 2024", want},
+		{"  2024-05-01T13:30:15Z\n", want},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimestamp(tt.in)
+		if err != nil {
+			t.Errorf("ParseTimestamp(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) || got.Location() != time.UTC {
+			t.Errorf("ParseTimestamp(%q) = %v, want %v in UTC", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "yesterday", "2024-05-01", "2024-13-01T00:00:00Z", "01/05/2024 13:30"} {
+		if got, err := ParseTimestamp(in); err == nil {
+			t.Errorf("ParseTimestamp(%q) = %v, want an error", in, got)
+		}
+	}
+}
+
+func TestSearchResultCreatedAt(t *testing.T) {
+	want := time.Date(2024, 5, 1, 13, 30, 15, 0, time.UTC)
+	tests := []struct {
+		name string
+		json string
+		want time.Time
+	}{
+		{"RFC 3339 with offset", `{"created_at":"2024-05-01T15:30:15+02:00"}`, want},
+		{"legacy format", `{"created_at":"Wed May 01 13:30:15 +0000 2024"}`, want},
+		{"Unix seconds", `{"created_at":1714570215}`, want},
+		{"Unix milliseconds", `{"created_at":1714570215000}`, want},
+		{"null", `{"created_at":null}`, time.Time{}},
+		{"empty string", `{"created_at":""}`, time.Time{}},
+		{"missing", `{"id":"1"}`, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r SearchResult
+			if err := json.Unmarshal([]byte(tt.json), &r); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !r.CreatedAt.Equal(tt.want) {
+				t.Errorf("CreatedAt = %v, want %v", r.CreatedAt, tt.want)
+			}
+			if !r.CreatedAt.IsZero() && r.CreatedAt.Location() != time.UTC {
+				t.Errorf("CreatedAt is in %v, want UTC", r.CreatedAt.Location())
+			}
+		})
+	}
+
+	var r SearchResult
+	if err := json.Unmarshal([]byte(`{"id":"7","created_at":"last week"}`), &r); err == nil {
+		t.Error("Unmarshal accepted an invalid created_at")
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	at := time.Date(2024, 5, 1, 13, 30, 15, 0, time.UTC)
+	tests := []struct {
+		zone string
+		want string
+	}{
+		{"", "2024-05-01 13:30 UTC"},
+		{"utc", "2024-05-01 13:30 UTC"},
+		{"America/New_York", "2024-05-01 09:30 EDT"},
+		{"Asia/Tokyo", "2024-05-01 22:30 JST"},
+	}
+	for _, tt := range tests {
+		loc, err := LoadTimezone(tt.zone)
+		if err != nil {
+			t.Fatalf("LoadTimezone(%q): %v", tt.zone, err)
+		}
+		if got := FormatTimestamp(at, loc); got != tt.want {
+			t.Errorf("FormatTimestamp in %q = %q, want %q", tt.zone, got, tt.want)
+		}
+	}
+	if got := FormatTimestamp(at, nil); got != "2024-05-01 13:30 UTC" {
+		t.Errorf("FormatTimestamp with a nil location = %q, want UTC", got)
+	}
+
+	for _, zone := range []string{"Local", "Mars/Olympus_Mons"} {
+		if _, err := LoadTimezone(zone); err == nil {
+			t.Errorf("LoadTimezone(%q) succeeded, want an error", zone)
+		}
+	}
+}
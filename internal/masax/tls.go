@@ -0,0 +1,121 @@
+package masax
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// defaultTLSMinVersion is the oldest TLS version the client accepts unless
+// configured otherwise.
+const defaultTLSMinVersion = tls.VersionTLS12
+
+// WithTLSConfig sets the TLS configuration used to connect to the API, e.g.
+// for client certificates. The config is cloned, and WithTLSMinVersion and
+// WithRootCAs apply on top of it. A config without a MinVersion gets TLS 1.2.
+// Like WithProxy, it requires the Transport to be an *http.Transport;
+// NewClient returns an error if it isn't.
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *Client) {
+		if cfg != nil {
+			c.tls.config = cfg.Clone()
+		}
+	}
+}
+
+// WithTLSMinVersion sets the oldest TLS version the client accepts, e.g.
+// tls.VersionTLS13 (TLS 1.2 by default). Versions before TLS 1.2 are
+// rejected by NewClient.
+func WithTLSMinVersion(version uint16) ClientOption {
+	return func(c *Client) {
+		if version < tls.VersionTLS12 || version > tls.VersionTLS13 {
+			c.setOptionErr(fmt.Errorf("unsupported TLS minimum version %#04x: must be TLS 1.2 or 1.3", version))
+			return
+		}
+		c.tls.minVersion = version
+	}
+}
+
+// WithRootCAs sets the certificate authorities trusted to sign the API's
+// certificate, replacing the system roots, e.g. for an internal PKI.
+func WithRootCAs(pool *x509.CertPool) ClientOption {
+	return func(c *Client) {
+		if pool != nil {
+			c.tls.rootCAs = pool
+		}
+	}
+}
+
+// WithCAFile trusts the PEM-encoded CA certificates in the file at path, as
+// WithRootCAs does. NewClient returns an error if the file can't be read or
+// holds no certificates. An empty path is a no-op.
+func WithCAFile(path string) ClientOption {
+	return func(c *Client) {
+		if path == "" {
+			return
+		}
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			c.setOptionErr(fmt.Errorf("failed to read CA file: %w", err))
+			return
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			c.setOptionErr(fmt.Errorf("no PEM certificates found in CA file %q", path))
+			return
+		}
+		c.tls.rootCAs = pool
+	}
+}
+
+// ParseTLSVersion parses a TLS version such as "1.2" or "1.3". An empty
+// string yields 0, which keeps the default.
+func ParseTLSVersion(s string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "tls") {
+	case "":
+		return 0, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("unsupported TLS version %q: must be 1.2 or 1.3", s)
+}
+
+// tlsSettings holds the TLS options.
+type tlsSettings struct {
+	config     *tls.Config // From WithTLSConfig; nil keeps the transport's
+	minVersion uint16      // 0 keeps the config's, or the default
+	rootCAs    *x509.CertPool
+}
+
+// configured reports whether any TLS option was set.
+func (s tlsSettings) configured() bool {
+	return s.config != nil || s.minVersion != 0 || s.rootCAs != nil
+}
+
+// apply returns the TLS config for a transport currently using base, which
+// may be nil.
+func (s tlsSettings) apply(base *tls.Config) *tls.Config {
+	cfg := s.config
+	switch {
+	case cfg != nil:
+		cfg = cfg.Clone()
+	case base != nil:
+		cfg = base.Clone()
+	default:
+		cfg = &tls.Config{}
+	}
+	if s.minVersion != 0 {
+		cfg.MinVersion = s.minVersion
+	}
+	if cfg.MinVersion == 0 {
+		cfg.MinVersion = defaultTLSMinVersion
+	}
+	if s.rootCAs != nil {
+		cfg.RootCAs = s.rootCAs
+	}
+	return cfg
+}
@@ -0,0 +1,119 @@
+package masax
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTLSTestServer starts a TLS server answering every search with an empty
+// page and writes its self-signed certificate to a PEM file, returning the
+// server and the file's path.
+func newTLSTestServer(t *testing.T, maxVersion uint16) (*httptest.Server, string) {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, `{"items":[]}`)
+	}))
+	srv.TLS = &tls.Config{MaxVersion: maxVersion}
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0) // Failed handshakes are expected
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatalf("writing CA file: %v", err)
+	}
+	return srv, caFile
+}
+
+func TestTLSTrust(t *testing.T) {
+	srv, caFile := newTLSTestServer(t, 0)
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	tests := []struct {
+		name      string
+		opts      []ClientOption
+		untrusted bool
+	}{
+		{name: "CA file", opts: []ClientOption{WithCAFile(caFile)}},
+		{name: "root CAs", opts: []ClientOption{WithRootCAs(pool)}},
+		{name: "CA file with TLS 1.3", opts: []ClientOption{WithCAFile(caFile), WithTLSMinVersion(tls.VersionTLS13)}},
+		{name: "system roots", untrusted: true},
+		{name: "TLS config without CA", opts: []ClientOption{WithTLSConfig(&tls.Config{})}, untrusted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient("test-key", append([]ClientOption{WithBaseURL(srv.URL)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			_, err = c.Search(context.Background(), "tls", 10)
+			if !tt.untrusted {
+				if err != nil {
+					t.Fatalf("Search: %v", err)
+				}
+				return
+			}
+			var unknownAuthority x509.UnknownAuthorityError
+			if !errors.As(err, &unknownAuthority) {
+				t.Fatalf("Search error = %v, want an unknown authority error", err)
+			}
+		})
+	}
+}
+
+func TestTLSMinVersion(t *testing.T) {
+	srv, caFile := newTLSTestServer(t, tls.VersionTLS12)
+
+	c, err := NewClient("test-key", WithBaseURL(srv.URL), WithCAFile(caFile), WithTLSMinVersion(tls.VersionTLS13))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.Search(context.Background(), "tls", 10); err == nil {
+		t.Fatal("Search against a TLS 1.2 server succeeded with a TLS 1.3 minimum")
+	}
+
+	c, err = NewClient("test-key", WithBaseURL(srv.URL), WithCAFile(caFile))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := c.Search(context.Background(), "tls", 10); err != nil {
+		t.Fatalf("Search against a TLS 1.2 server with the default minimum: %v", err)
+	}
+}
+
+func TestTLSOptionErrors(t *testing.T) {
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opt  ClientOption
+	}{
+		{"TLS 1.0", WithTLSMinVersion(tls.VersionTLS10)},
+		{"TLS 1.1", WithTLSMinVersion(tls.VersionTLS11)},
+		{"unknown version", WithTLSMinVersion(0x0305)},
+		{"missing CA file", WithCAFile(filepath.Join(t.TempDir(), "missing.pem"))},
+		{"CA file without PEM", WithCAFile(notPEM)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewClient("test-key", tt.opt); err == nil {
+				t.Error("NewClient succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package masax
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "masax-mcp/internal/masax"
+
+// WithTracerProvider enables OpenTelemetry tracing of API calls using tp. By
+// default a no-op provider is used and no spans are recorded.
+func WithTracerProvider(tp trace.TracerProvider) ClientOption {
+	return func(c *Client) {
+		if tp != nil {
+			c.tracer = tp.Tracer(tracerName)
+		}
+	}
+}
+
+// defaultTracer returns a tracer that records nothing.
+func defaultTracer() trace.Tracer {
+	return noop.NewTracerProvider().Tracer(tracerName)
+}
+
+// startSearchSpan starts a span covering the API calls for searchReq. It
+// nests under any span already in ctx.
+func (c *Client) startSearchSpan(ctx context.Context, searchReq SearchRequest) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, "masax.Search",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.Int("masax.query.length", len(searchReq.Query)),
+			attribute.Int("masax.max_results", searchReq.MaxResults),
+		),
+	)
+}
+
+// endSearchSpan records the outcome of the API calls on span and ends it.
+func endSearchSpan(span trace.Span, stats fetchStats, err error) {
+	span.SetAttributes(
+		attribute.Int("http.response.status_code", stats.statusCode),
+		attribute.Int("masax.retry_count", stats.retries),
+	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,105 @@
+package masax
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Default connection pool settings. All traffic goes to a single API host,
+// so the per-host idle limit is raised well above net/http's default of 2,
+// letting bursts of concurrent tool calls reuse connections instead of
+// opening (and TLS-handshaking) new ones.
+const (
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 32
+	defaultIdleConnTimeout     = 90 * time.Second
+)
+
+// newDefaultTransport returns the Transport used when no http.Client or
+// Transport is supplied. It starts from http.DefaultTransport, so proxy
+// environment variables, dial timeouts and HTTP/2 still apply, and tunes
+// pooling for bursty traffic to one host. Compression stays enabled, so
+// the transport requests and transparently decodes gzip. TLS versions before
+// 1.2 are refused.
+func newDefaultTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = &tls.Config{MinVersion: defaultTLSMinVersion}
+	t.MaxIdleConns = defaultMaxIdleConns
+	t.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	t.IdleConnTimeout = defaultIdleConnTimeout
+	t.DisableCompression = false
+	return t
+}
+
+// WithTransport sets the RoundTripper used for requests. It applies on top
+// of WithHTTPClient, replacing that client's Transport without modifying the
+// client passed in.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		if rt != nil {
+			c.transport = rt
+		}
+	}
+}
+
+// WithProxy routes requests through the HTTP or HTTPS proxy at proxyURL,
+// overriding HTTP_PROXY, HTTPS_PROXY and NO_PROXY, which are otherwise
+// respected. It requires the Transport to be an *http.Transport; NewClient
+// returns an error if it isn't or if proxyURL is invalid.
+func WithProxy(proxyURL string) ClientOption {
+	return func(c *Client) {
+		u, err := url.Parse(proxyURL)
+		if err == nil && (u.Scheme != "http" && u.Scheme != "https" || u.Host == "") {
+			err = fmt.Errorf("must be an absolute http or https URL")
+		}
+		if err != nil {
+			c.setOptionErr(fmt.Errorf("invalid proxy URL %q: %w", proxyURL, err))
+			return
+		}
+		c.proxyURL = u
+	}
+}
+
+// setOptionErr records err for NewClient to return, keeping the first.
+func (c *Client) setOptionErr(err error) {
+	if c.optionErr == nil {
+		c.optionErr = err
+	}
+}
+
+// configureTransport applies WithTransport, WithProxy, the TLS options and
+// the connection timeout options to a copy of the HTTP client, leaving any
+// client passed to WithHTTPClient unmodified.
+func (c *Client) configureTransport() error {
+	if c.transport == nil && c.proxyURL == nil && !c.tls.configured() && !c.dial.configured() {
+		return nil
+	}
+	httpClient := *c.httpClient
+	if c.transport != nil {
+		httpClient.Transport = c.transport
+	}
+	if c.proxyURL != nil || c.tls.configured() || c.dial.configured() {
+		var t *http.Transport
+		switch rt := httpClient.Transport.(type) {
+		case nil:
+			t = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			t = rt.Clone()
+		default:
+			return fmt.Errorf("WithProxy, the TLS options and the connection timeout options require an *http.Transport, got %T", rt)
+		}
+		if c.proxyURL != nil {
+			t.Proxy = http.ProxyURL(c.proxyURL)
+		}
+		if c.tls.configured() {
+			t.TLSClientConfig = c.tls.apply(t.TLSClientConfig)
+		}
+		c.dial.apply(t)
+		httpClient.Transport = t
+	}
+	c.httpClient = &httpClient
+	return nil
+}
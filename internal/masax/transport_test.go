@@ -0,0 +1,171 @@
+package masax
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestProxy(t *testing.T) {
+	var proxied []*http.Request
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.Clone(context.Background()))
+		writeJSON(w, `{"items":[{"id":"1","text":"via proxy"}]}`)
+	}))
+	defer proxy.Close()
+
+	// The API host doesn't resolve, so only the proxy can answer.
+	const apiURL = "http://api.masa.invalid"
+	httpClient := &http.Client{Transport: &http.Transport{}}
+	c, err := NewClient("test-key", WithBaseURL(apiURL), WithAllowInsecure(), WithHTTPClient(httpClient), WithProxy(proxy.URL))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	resp, err := c.Search(context.Background(), "proxied", 10)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].Text != "via proxy" {
+		t.Errorf("Items = %+v, want the proxy's response", resp.Items)
+	}
+
+	if len(proxied) != 1 {
+		t.Fatalf("proxy got %d requests, want 1", len(proxied))
+	}
+	req := proxied[0]
+	if got, want := req.URL.String(), apiURL+defaultSearchPath; got != want {
+		t.Errorf("proxied URL = %s, want %s", got, want)
+	}
+	if req.Method != http.MethodPost {
+		t.Errorf("proxied method = %s, want POST", req.Method)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer test-key" {
+		t.Errorf("proxied Authorization = %q, want the API key", got)
+	}
+	if httpClient.Transport.(*http.Transport).Proxy != nil {
+		t.Error("WithProxy modified the transport of the client passed to WithHTTPClient")
+	}
+}
+
+func TestTransportOptionErrors(t *testing.T) {
+	custom := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		t.Error("custom transport was used")
+		return nil, http.ErrNotSupported
+	})
+	tests := []struct {
+		name string
+		opts []ClientOption
+	}{
+		{"proxy with custom transport", []ClientOption{WithTransport(custom), WithProxy("http://proxy.internal:3128")}},
+		{"proxy with custom client transport", []ClientOption{WithHTTPClient(&http.Client{Transport: custom}), WithProxy("http://proxy.internal:3128")}},
+		{"TLS option with custom transport", []ClientOption{WithTransport(custom), WithTLSMinVersion(0x0304)}},
+		{"connect timeout with custom transport", []ClientOption{WithTransport(custom), WithConnectTimeout(time.Second)}},
+		{"proxy without scheme", []ClientOption{WithProxy("proxy.internal:3128")}},
+		{"proxy with unsupported scheme", []ClientOption{WithProxy("ftp://proxy.internal")}},
+		{"proxy without host", []ClientOption{WithProxy("http://")}},
+		{"empty proxy", []ClientOption{WithProxy("")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewClient("test-key", tt.opts...); err == nil {
+				t.Error("NewClient succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestCustomTransport(t *testing.T) {
+	var calls int
+	base := http.DefaultTransport
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, `{"items":[]}`)
+	}, WithTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return base.RoundTrip(req)
+	})))
+	if _, err := c.Search(context.Background(), "custom", 10); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("custom transport calls = %d, want 1", calls)
+	}
+}
+
+// BenchmarkSearch measures searches against a local server arriving in
+// bursts of concurrent calls, as from an MCP client fanning out tool calls,
+// with the default pooled transport, with net/http's default of 2 idle
+// connections per host, without keep-alives, and served from the cache.
+// conns/op is the number of connections opened per search.
+func BenchmarkSearch(b *testing.B) {
+	const burst = 16
+
+	netHTTPDefaults := http.DefaultTransport.(*http.Transport).Clone()
+	netHTTPDefaults.MaxIdleConnsPerHost = 0 // http.DefaultMaxIdleConnsPerHost
+	noKeepAlive := http.DefaultTransport.(*http.Transport).Clone()
+	noKeepAlive.DisableKeepAlives = true
+
+	benchmarks := []struct {
+		name    string
+		opts    []ClientOption
+		queries int // Distinct queries cycled through; 0 makes each search unique
+	}{
+		{name: "pooled"},
+		{name: "net-http-defaults", opts: []ClientOption{WithTransport(netHTTPDefaults)}},
+		{name: "no-keep-alive", opts: []ClientOption{WithTransport(noKeepAlive)}},
+		{name: "cached", opts: []ClientOption{WithCache(100, time.Hour)}, queries: burst},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			var conns atomic.Int64
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(io.Discard, r.Body)
+				writeJSON(w, `{"items":[{"id":"1","text":"benchmark"}]}`)
+			}))
+			srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					conns.Add(1)
+				}
+			}
+			srv.Start()
+			defer srv.Close()
+
+			c, err := NewClient("test-key", append([]ClientOption{WithBaseURL(srv.URL)}, bm.opts...)...)
+			if err != nil {
+				b.Fatalf("NewClient: %v", err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i += burst {
+				var wg sync.WaitGroup
+				for j := i; j < min(i+burst, b.N); j++ {
+					n := j
+					if bm.queries > 0 {
+						n %= bm.queries
+					}
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						// Distinct queries, so concurrent searches aren't coalesced
+						if _, err := c.Search(context.Background(), "bench "+strconv.Itoa(n), 10); err != nil {
+							b.Error(err)
+						}
+					}()
+				}
+				wg.Wait()
+			}
+			b.StopTimer()
+			b.ReportMetric(float64(conns.Load())/float64(b.N), "conns/op")
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package masax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUsageUnknown is returned by Usage before any response carrying
+// rate-limit headers has been seen.
+var ErrUsageUnknown = errors.New("masa X API: usage unknown")
+
+// Usage is the API quota as reported by the rate-limit headers of the most
+// recent response. Fields the API didn't report are nil or zero.
+type Usage struct {
+	Limit      *int       `json:"limit,omitempty"`
+	Remaining  *int       `json:"remaining,omitempty"`
+	ResetAt    *time.Time `json:"reset_at,omitempty"`
+	ObservedAt time.Time  `json:"observed_at"`
+}
+
+// usageTracker remembers the last Usage seen. It is safe for concurrent use.
+type usageTracker struct {
+	mu   sync.Mutex
+	last *Usage
+}
+
+// Usage returns the quota reported by the most recent API response. The
+// Masa X API has no usage endpoint, so this reflects the X-RateLimit-* or
+// RateLimit-* headers captured from searches and pings; it returns an error
+// wrapping ErrUsageUnknown if none have been seen yet.
+func (c *Client) Usage(ctx context.Context) (*Usage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.usage.mu.Lock()
+	defer c.usage.mu.Unlock()
+	if c.usage.last == nil {
+		return nil, fmt.Errorf("%w: no response with rate-limit headers has been received yet", ErrUsageUnknown)
+	}
+	u := *c.usage.last
+	return &u, nil
+}
+
+// record updates the tracker from a response's headers, ignoring responses
+// without rate-limit information.
+func (t *usageTracker) record(h http.Header, now time.Time) {
+	u := Usage{ObservedAt: now}
+	u.Limit = rateLimitHeaderInt(h, "Limit")
+	u.Remaining = rateLimitHeaderInt(h, "Remaining")
+	if reset := rateLimitHeaderInt(h, "Reset"); reset != nil {
+		resetAt := resetTime(*reset, now)
+		u.ResetAt = &resetAt
+	}
+	if u.Limit == nil && u.Remaining == nil && u.ResetAt == nil {
+		return
+	}
+	t.mu.Lock()
+	t.last = &u
+	t.mu.Unlock()
+}
+
+// rateLimitHeaderInt parses X-RateLimit-{name} or, failing that, the IETF
+// draft's RateLimit-{name}.
+func rateLimitHeaderInt(h http.Header, name string) *int {
+	for _, key := range []string{"X-RateLimit-" + name, "RateLimit-" + name} {
+		if v := strings.TrimSpace(h.Get(key)); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+				return &n
+			}
+		}
+	}
+	return nil
+}
+
+// epochThreshold separates reset values given as Unix timestamps from ones
+// given as seconds remaining; no window lasts anywhere near this long.
+const epochThreshold = 1_000_000_000
+
+// resetTime interprets a rate-limit reset value either as a Unix timestamp
+// or as seconds from now.
+func resetTime(v int, now time.Time) time.Time {
+	if v >= epochThreshold {
+		return time.Unix(int64(v), 0).UTC()
+	}
+	return now.Add(time.Duration(v) * time.Second).UTC()
+}
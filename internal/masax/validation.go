@@ -0,0 +1,68 @@
+package masax
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidResponse is returned, wrapped with the problems found, when
+// WithStrictValidation is set and a search response lacks fields the client
+// relies on.
+var ErrInvalidResponse = errors.New("masa X API: response doesn't match the expected shape")
+
+// maxReportedProblems bounds how many problems a validation error lists.
+const maxReportedProblems = 3
+
+// WithStrictValidation checks each decoded search response for the shape
+// the client expects: an items array, each item with an ID and a valid
+// created_at. A response that fails is returned as an error wrapping
+// ErrInvalidResponse, describing the problems, rather than as results with
+// empty fields, so upstream contract changes surface at once. Unknown
+// fields are still ignored. Empty bodies and 204 responses still mean no
+// results. By default responses are used as decoded, with missing fields
+// left zero.
+func WithStrictValidation() ClientOption {
+	return func(c *Client) {
+		c.strictValidation = true
+	}
+}
+
+// validateSearchResponse checks a decoded search response against the
+// shape described in WithStrictValidation, given the body it was decoded
+// from, which tells a missing items array from an empty one.
+func validateSearchResponse(body []byte, resp *SearchResponse) error {
+	var shape map[string]json.RawMessage
+	if err := json.Unmarshal(body, &shape); err != nil {
+		return fmt.Errorf("%w: body is not a JSON object", ErrInvalidResponse)
+	}
+	items, ok := shape["items"]
+	if !ok {
+		return fmt.Errorf("%w: missing items array", ErrInvalidResponse)
+	}
+	if items = bytes.TrimSpace(items); len(items) == 0 || items[0] != '[' {
+		return fmt.Errorf("%w: items is %s, not an array", ErrInvalidResponse, bodyExcerpt(items))
+	}
+
+	var problems []string
+	for i, item := range resp.Items {
+		switch {
+		case item.ID == "":
+			problems = append(problems, fmt.Sprintf("item %d has no id", i))
+		case item.CreatedAt.IsZero():
+			problems = append(problems, fmt.Sprintf("item %d (id %s) has no created_at", i, item.ID))
+		}
+	}
+	if resp.Metadata.TotalResults < 0 {
+		problems = append(problems, fmt.Sprintf("metadata.total_results is negative (%d)", resp.Metadata.TotalResults))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	if len(problems) > maxReportedProblems {
+		problems = append(problems[:maxReportedProblems], fmt.Sprintf("and %d more", len(problems)-maxReportedProblems))
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidResponse, strings.Join(problems, "; "))
+}
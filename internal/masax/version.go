@@ -0,0 +1,51 @@
+package masax
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// apiVersionPattern matches API version path segments such as "v1".
+var apiVersionPattern = regexp.MustCompile(`^v[1-9][0-9]*$`)
+
+// WithAPIVersion targets API version v, e.g. "v2", by replacing the version
+// segment of the base URL (v1 by default), whether it is the default or set
+// with WithBaseURL. NewClient returns an error if v isn't of the form "vN"
+// or the base URL has no version segment. An empty v is ignored.
+func WithAPIVersion(v string) ClientOption {
+	return func(c *Client) {
+		if v == "" {
+			return
+		}
+		if !apiVersionPattern.MatchString(v) {
+			c.setOptionErr(fmt.Errorf("invalid API version %q: must be of the form v1, v2, ...", v))
+			return
+		}
+		c.apiVersion = v
+	}
+}
+
+// applyAPIVersion rewrites the last version segment of the base URL's path
+// to c.apiVersion.
+func (c *Client) applyAPIVersion() error {
+	if c.apiVersion == "" {
+		return nil
+	}
+	u, err := url.Parse(c.apiBaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL %q: %w", c.apiBaseURL, err)
+	}
+	segments := strings.Split(u.Path, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if apiVersionPattern.MatchString(segments[i]) {
+			segments[i] = c.apiVersion
+			u.Path = strings.Join(segments, "/")
+			u.RawPath = ""
+			c.apiBaseURL = u.String()
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot set API version %s: base URL %q has no version segment", c.apiVersion, c.apiBaseURL)
+}
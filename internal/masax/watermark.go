@@ -0,0 +1,79 @@
+package masax
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Watermark marks the newest post seen by an incremental poll, so the next
+// poll can pick out only newer posts.
+type Watermark struct {
+	ID        string
+	CreatedAt time.Time
+}
+
+// MarshalJSON renders w as {"id": ..., "created_at": ...}, leaving out
+// whichever is unknown.
+func (w Watermark) MarshalJSON() ([]byte, error) {
+	out := struct {
+		ID        string     `json:"id,omitempty"`
+		CreatedAt *time.Time `json:"created_at,omitempty"`
+	}{ID: w.ID}
+	if !w.CreatedAt.IsZero() {
+		out.CreatedAt = &w.CreatedAt
+	}
+	return json.Marshal(out)
+}
+
+// WatermarkOf returns the watermark of a single result.
+func WatermarkOf(r SearchResult) Watermark {
+	return Watermark{ID: r.ID, CreatedAt: r.CreatedAt}
+}
+
+// IsZero reports whether w marks nothing, in which case every post is newer.
+func (w Watermark) IsZero() bool {
+	return w.ID == "" && w.CreatedAt.IsZero()
+}
+
+// Before reports whether r is newer than w. Post IDs increase over time, so
+// they're compared when both are known; otherwise creation times are.
+func (w Watermark) Before(r SearchResult) bool {
+	switch {
+	case w.ID != "" && r.ID != "":
+		return compareIDs(r.ID, w.ID) > 0
+	case !w.CreatedAt.IsZero():
+		return r.CreatedAt.After(w.CreatedAt)
+	default:
+		return true
+	}
+}
+
+// Later returns whichever of w and o marks the newer post.
+func (w Watermark) Later(o Watermark) Watermark {
+	if w.Before(SearchResult{ID: o.ID, CreatedAt: o.CreatedAt}) {
+		return o
+	}
+	return w
+}
+
+// Advance returns the watermark moved up to the newest of items, or w itself
+// if none is newer.
+func (w Watermark) Advance(items []SearchResult) Watermark {
+	for _, item := range items {
+		if w.Before(item) {
+			w = WatermarkOf(item)
+		}
+	}
+	return w
+}
+
+// NewerThan returns the items newer than w, in their original order.
+func NewerThan(items []SearchResult, w Watermark) []SearchResult {
+	newer := make([]SearchResult, 0, len(items))
+	for _, item := range items {
+		if w.Before(item) {
+			newer = append(newer, item)
+		}
+	}
+	return newer
+}
@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const defaultTopAuthors = 10
+
+// topAuthorsReport is the JSON payload returned by the top authors tool.
+type topAuthorsReport struct {
+	Query     string              `json:"query"`
+	PostsSeen int                 `json:"posts_analyzed"`
+	SearchURI string              `json:"search_uri"`
+	Authors   []masax.AuthorStats `json:"authors"`
+}
+
+// handleMasaXTopAuthors runs a search and returns the authors with the most
+// total engagement in the results.
+func (s *MCPServer) handleMasaXTopAuthors(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := queryArg(request, "query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	maxResults, err := s.maxResultsArg(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	topN, err := nonNegativeIntArg(request, "top_n")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if topN == 0 {
+		topN = defaultTopAuthors
+	}
+
+	log.Printf("Received top authors request for query: '%s', max_results: %d", query, maxResults)
+
+	params := searchParams{Query: query, MaxResults: maxResults}
+	searchResponse, err := s.search(ctx, params)
+	if err != nil {
+		logSearchError(ctx, err)
+		return searchErrorResult(err), nil
+	}
+	searchID := s.saveSearch(params, searchResponse)
+
+	jsonData, err := json.MarshalIndent(topAuthorsReport{
+		Query:     query,
+		PostsSeen: len(searchResponse.Items),
+		SearchURI: searchResultResourcePrefix + searchID,
+		Authors:   masax.TopAuthors(searchResponse.Items, topN),
+	}, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal authors: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
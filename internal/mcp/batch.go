@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	maxBatchQueries  = 10
+	batchConcurrency = 4
+)
+
+// handleMasaXSearchBatch runs several searches with bounded parallelism and
+// returns one embedded resource per successful query. The tool only reports
+// an error if every query fails.
+func (s *MCPServer) handleMasaXSearchBatch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	queries, err := stringSliceArg(request, "queries")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if len(queries) == 0 || len(queries) > maxBatchQueries {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid 'queries' argument: must contain between 1 and %d queries", maxBatchQueries)), nil
+	}
+	for i, query := range queries {
+		if queries[i], err = normalizeQueryArg("queries", query); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	maxResults, err := s.maxResultsArg(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	log.Printf("Received batch search request for %d queries, max_results: %d", len(queries), maxResults)
+
+	responses, errs := s.masaClient.SearchBatch(ctx, queries, maxResults, batchConcurrency)
+
+	var summary []string
+	var contents []mcp.Content
+	for i, query := range queries {
+		if errs[i] != nil {
+			logSearchError(ctx, fmt.Errorf("batch query '%s': %w", query, errs[i]))
+			summary = append(summary, fmt.Sprintf("- '%s': failed: %s", query, toolErrorMessage(errs[i])))
+			continue
+		}
+		_, resultContents, err := s.storeSearchResult(searchParams{Query: query, MaxResults: maxResults}, responses[i])
+		if err != nil {
+			summary = append(summary, fmt.Sprintf("- '%s': failed to marshal results: %v", query, err))
+			continue
+		}
+		summary = append(summary, fmt.Sprintf("- '%s': %d results at %s", query, len(responses[i].Items), resultContents.URI))
+		contents = append(contents, mcp.NewEmbeddedResource(resultContents))
+	}
+
+	text := fmt.Sprintf("Masa X batch search results (%d of %d queries succeeded):\n%s",
+		len(contents), len(queries), strings.Join(summary, "\n"))
+	return &mcp.CallToolResult{
+		Content: append([]mcp.Content{mcp.NewTextContent(text)}, contents...),
+		IsError: len(contents) == 0,
+	}, nil
+}
+
+// stringSliceArg extracts a required array-of-strings argument, rejecting
+// empty or non-string elements.
+func stringSliceArg(request mcp.CallToolRequest, name string) ([]string, error) {
+	raw, ok := request.Params.Arguments[name].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Missing or invalid '%s' argument: must be an array of strings", name)
+	}
+	values := make([]string, 0, len(raw))
+	for _, v := range raw {
+		str, ok := v.(string)
+		if !ok || strings.TrimSpace(str) == "" {
+			return nil, fmt.Errorf("Invalid '%s' argument: every element must be a non-empty string", name)
+		}
+		values = append(values, str)
+	}
+	return values, nil
+}
@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"fmt"
+
+	"masax-mcp/internal/masax"
+)
+
+// defaultResultBudget is the default size limit for search results embedded
+// in a tool response: about 25k tokens at roughly 4 bytes per token.
+const defaultResultBudget = 100_000
+
+// WithResultBudget limits the search results embedded in a search tool
+// response to about n bytes of JSON (100,000 by default, roughly 25k
+// tokens). Larger results are cut down to the top items that fit, with a
+// note saying how many were omitted; the stored results behind the resource
+// URI stay complete.
+func WithResultBudget(n int) ServerOption {
+	return func(s *MCPServer) {
+		if n > 0 {
+			s.resultBudget = n
+		}
+	}
+}
+
+// fitResults renders as many of resp's leading items as fit in the result
+// budget, returning the JSON and the number of items kept. It is only called
+// once the full rendering has been found to be too large.
+func (s *MCPServer) fitResults(resp *masax.SearchResponse, params searchParams) ([]byte, int, error) {
+	render := func(n int) ([]byte, error) {
+		truncated := *resp
+		truncated.Items = resp.Items[:n]
+		return params.marshalResults(annotatedResponse{&truncated, fetchedFreshness(resp)})
+	}
+
+	// Binary search for the largest prefix that fits; zero items always "fits"
+	lo, hi := 0, len(resp.Items)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		data, err := render(mid)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(data) <= s.resultBudget {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	data, err := render(lo)
+	return data, lo, err
+}
+
+// truncationNote explains results cut down by fitResults.
+func truncationNote(kept int, resp *masax.SearchResponse, uri string) string {
+	note := fmt.Sprintf("Showing the top %d of %d results; %d were omitted to fit the response size budget", kept, len(resp.Items), len(resp.Items)-kept)
+	if total := resp.Metadata.TotalResults; total > 0 {
+		note += fmt.Sprintf(" (the API reports %d matching results in total)", total)
+	}
+	return note + fmt.Sprintf(". Read %s?%s=1 to page through all of them.", uri, pageParam)
+}
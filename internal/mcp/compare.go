@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// comparisonSide is one query's half of the compare tool's payload.
+type comparisonSide struct {
+	Query     string `json:"query"`
+	SearchURI string `json:"search_uri"`
+	masax.EngagementStats
+}
+
+// comparison is the JSON payload returned by the compare tool. Leader names
+// the query with the higher average engagement per post, or is "tie".
+type comparison struct {
+	A      comparisonSide `json:"a"`
+	B      comparisonSide `json:"b"`
+	Leader string         `json:"leader"`
+}
+
+// handleMasaXCompare runs two searches as a batch and returns their
+// engagement statistics side by side. Unlike the batch tool it fails if
+// either search fails, since half a comparison isn't useful.
+func (s *MCPServer) handleMasaXCompare(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	queries := make([]string, 2)
+	for i, name := range []string{"query_a", "query_b"} {
+		query, err := queryArg(request, name)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		queries[i] = query
+	}
+	maxResults, err := s.maxResultsArg(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	log.Printf("Received compare request for queries: '%s' vs '%s', max_results: %d", queries[0], queries[1], maxResults)
+
+	responses, errs := s.masaClient.SearchBatch(ctx, queries, maxResults, len(queries))
+	for i, query := range queries {
+		if errs[i] != nil {
+			err := fmt.Errorf("compare query '%s': %w", query, errs[i])
+			logSearchError(ctx, err)
+			return searchErrorResult(err), nil
+		}
+	}
+
+	sides := make([]comparisonSide, len(queries))
+	for i, query := range queries {
+		searchID := s.saveSearch(searchParams{Query: query, MaxResults: maxResults}, responses[i])
+		sides[i] = comparisonSide{
+			Query:           query,
+			SearchURI:       searchResultResourcePrefix + searchID,
+			EngagementStats: masax.SummarizeEngagement(responses[i].Items),
+		}
+	}
+
+	result := comparison{A: sides[0], B: sides[1], Leader: "tie"}
+	switch {
+	case result.A.AvgEngagement > result.B.AvgEngagement:
+		result.Leader = "a"
+	case result.B.AvgEngagement > result.A.AvgEngagement:
+		result.Leader = "b"
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal comparison: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// debugRequest is the JSON payload returned by the debug request tool.
+type debugRequest struct {
+	Method  string               `json:"method"`
+	URL     string               `json:"url"`
+	Headers http.Header          `json:"headers"` // Credentials are redacted
+	Body    *masax.SearchRequest `json:"body"`    // null for GET, whose parameters are in the URL
+}
+
+// handleMasaXDebugRequest returns the request a search would send, built by
+// the client's own dry-run path so it matches what is actually sent, without
+// sending it.
+func (s *MCPServer) handleMasaXDebugRequest(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := queryArg(request, "query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	maxResults, err := s.maxResultsArg(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	log.Printf("Received debug request for query: '%s', max_results: %d", query, maxResults)
+
+	params := searchParams{Query: query, MaxResults: maxResults, DryRun: true}
+	searchResponse, err := s.masaClient.Search(ctx, query, maxResults, params.searchOptions()...)
+	if err != nil {
+		logSearchError(ctx, err)
+		return searchErrorResult(err), nil
+	}
+	if searchResponse.DryRun == nil {
+		return mcp.NewToolResultError("The search client doesn't support dry runs, so the request can't be shown"), nil
+	}
+
+	dryRun := searchResponse.DryRun
+	debug := debugRequest{Method: dryRun.Method, URL: dryRun.URL, Headers: dryRun.Headers}
+	if dryRun.Method != http.MethodGet {
+		debug.Body = &dryRun.Body
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false) // Keep the URL's & readable
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(debug); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal request: %v", err)), nil
+	}
+	return mcp.NewToolResultText("No request was sent to the Masa X API. A search would send:\n" + strings.TrimSuffix(buf.String(), "\n")), nil
+}
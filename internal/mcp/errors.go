@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Error categories reported in the _meta of failed tool results, so clients
+// can decide what to do without parsing the message.
+const (
+	errCategoryAuth           = "auth"            // Bad or insufficient credentials; retrying won't help
+	errCategoryRateLimited    = "rate_limited"    // Retry after waiting
+	errCategoryTransient      = "transient"       // 5xx, timeouts, network failures and an open circuit; retry later
+	errCategoryInvalidRequest = "invalid_request" // The API rejected the arguments
+	errCategoryNotFound       = "not_found"
+	errCategoryCanceled       = "canceled"
+	errCategoryUnsupported    = "unsupported" // The API doesn't offer the operation
+	errCategoryUnknown        = "unknown"
+)
+
+// errorCategory maps a Masa X client error to one of the errCategory values.
+func errorCategory(err error) string {
+	var apiErr *masax.APIError
+	switch {
+	case errors.Is(err, masax.ErrUnauthorized), errors.Is(err, masax.ErrForbidden):
+		return errCategoryAuth
+	case errors.Is(err, masax.ErrRateLimited):
+		return errCategoryRateLimited
+	case errors.Is(err, masax.ErrInvalidQuery):
+		return errCategoryInvalidRequest
+	case errors.Is(err, masax.ErrNotSupported):
+		return errCategoryUnsupported
+	case errors.Is(err, masax.ErrEmptyResponse):
+		return errCategoryNotFound // A lookup with nothing to return
+	case errors.Is(err, masax.ErrCircuitOpen), errors.Is(err, context.DeadlineExceeded):
+		return errCategoryTransient
+	case errors.Is(err, context.Canceled):
+		return errCategoryCanceled
+	case errors.As(err, &apiErr):
+		switch {
+		case apiErr.StatusCode >= http.StatusInternalServerError:
+			return errCategoryTransient
+		case apiErr.StatusCode == http.StatusNotFound:
+			return errCategoryNotFound
+		default:
+			return errCategoryInvalidRequest
+		}
+	}
+	switch masax.ErrorCategory(err) {
+	case "timeout", "network":
+		return errCategoryTransient
+	}
+	return errCategoryUnknown
+}
+
+// searchErrorResult builds the tool result for a failed search: the message
+// from toolErrorMessage for the LLM, plus the error category, whether a retry
+// may succeed and any HTTP status in _meta for the client.
+func searchErrorResult(err error) *mcp.CallToolResult {
+	category := errorCategory(err)
+	result := mcp.NewToolResultError(toolErrorMessage(err))
+	result.Meta = map[string]interface{}{
+		"error_category": category,
+		"retryable":      category == errCategoryTransient || category == errCategoryRateLimited,
+	}
+	var apiErr *masax.APIError
+	if errors.As(err, &apiErr) {
+		result.Meta["status_code"] = apiErr.StatusCode
+		if retryAfter := apiErr.Header.Get("Retry-After"); retryAfter != "" {
+			result.Meta["retry_after"] = retryAfter
+		}
+	}
+	return result
+}
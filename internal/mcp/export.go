@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// unsafeFilenameChars matches the characters dropped from a search_id to form
+// a filename, which leaves no path separators or dots to traverse with.
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// WithExportDir enables the export tool, which writes stored search results
+// to files in dir. The directory is created on first export if needed.
+// Without it, the tool isn't offered.
+func WithExportDir(dir string) ServerOption {
+	return func(s *MCPServer) {
+		if dir == "" {
+			return
+		}
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		s.exportDir = filepath.Clean(dir)
+	}
+}
+
+// exportFilename derives the export filename for a search_id and format, or
+// returns an error if nothing usable is left after sanitizing.
+func exportFilename(searchID, format string) (string, error) {
+	name := unsafeFilenameChars.ReplaceAllString(searchID, "")
+	if name == "" {
+		return "", fmt.Errorf("Invalid '%s' argument '%s'", searchIDParam, searchID)
+	}
+	return fmt.Sprintf("masax-search-%s.%s", name, format), nil
+}
+
+// exportPath joins name to the export directory, refusing any result outside
+// it.
+func (s *MCPServer) exportPath(name string) (string, error) {
+	path := filepath.Join(s.exportDir, name)
+	rel, err := filepath.Rel(s.exportDir, path)
+	if err != nil || rel != name || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("export path for '%s' escapes the export directory", name)
+	}
+	return path, nil
+}
+
+// handleMasaXExport writes the stored results of a search to a JSON or CSV
+// file in the export directory and returns its path, so large result sets
+// needn't be passed through the conversation.
+func (s *MCPServer) handleMasaXExport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	searchID, ok := request.Params.Arguments[searchIDParam].(string)
+	if !ok || strings.TrimSpace(searchID) == "" {
+		return mcp.NewToolResultError(fmt.Sprintf("Missing or invalid '%s' argument", searchIDParam)), nil
+	}
+	searchID = strings.TrimSpace(searchID)
+	format, _ := request.Params.Arguments["format"].(string)
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid 'format' argument '%s': must be 'json' or 'csv'", format)), nil
+	}
+
+	log.Printf("Received export request for search id: %s, format: %s", searchID, format)
+
+	stored, ok := s.searches.get(searchID)
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("No stored results for search id '%s'; run %s again", searchID, searchToolName)), nil
+	}
+
+	var buf bytes.Buffer
+	if format == "csv" {
+		if err := masax.WriteCSV(&buf, stored.response); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to render CSV: %v", err)), nil
+		}
+	} else {
+		jsonData, err := stored.params.marshalResults(annotatedResponse{stored.response, storedFreshness(stored.response)})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal Masa X response: %v", err)), nil
+		}
+		buf.Write(jsonData)
+	}
+
+	name, err := exportFilename(searchID, format)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	path, err := s.exportPath(name)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := writeFileAtomic(path, buf.Bytes()); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to write export: %v", err)), nil
+	}
+
+	uri := (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+	return mcp.NewToolResultText(fmt.Sprintf("Exported %d results for query '%s' as %s (%d bytes) to %s\nURI: %s",
+		len(stored.response.Items), stored.params.Query, strings.ToUpper(format), buf.Len(), path, uri)), nil
+}
+
+// writeFileAtomic writes data to path through a temporary file in the same
+// directory, so readers never see a partial export.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".export-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"masax-mcp/internal/masax"
+)
+
+// resultFields are the JSON names of masax.SearchResult's fields, the valid
+// values of the search tool's fields argument.
+var resultFields = jsonFieldNames(reflect.TypeOf(masax.SearchResult{}))
+
+// metricFields are the JSON names of masax.PublicMetrics's fields, the
+// valid values of the search tool's metrics argument.
+var metricFields = jsonFieldNames(reflect.TypeOf(masax.PublicMetrics{}))
+
+// jsonFieldNames returns the JSON names of t's exported fields.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// parseFields parses a comma-separated list of result fields, returning them
+// sorted and without duplicates. "id" is always included so results can be
+// referred to. An empty list means all fields.
+func parseFields(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	return parseNames(raw, "fields", "field", resultFields, map[string]bool{"id": true})
+}
+
+// parseMetrics parses a comma-separated list of public metrics, returning
+// them sorted and without duplicates. An empty list means all metrics.
+func parseMetrics(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	return parseNames(raw, "metrics", "metric", metricFields, map[string]bool{})
+}
+
+// parseNames adds the names in the comma-separated list raw, the argument
+// arg, to set, rejecting any not in valid, and returns the set sorted.
+func parseNames(raw, arg, noun string, valid, set map[string]bool) ([]string, error) {
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !valid[name] {
+			return nil, fmt.Errorf("Invalid '%s' argument: unknown %s %q (valid %ss: %s)", arg, noun, name, noun, strings.Join(sortedKeys(valid), ", "))
+		}
+		set[name] = true
+	}
+	return sortedKeys(set), nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// marshalResults renders v, a value with an "items" array of results such as
+// a *masax.SearchResponse or *resultPage, as JSON formatted for p: keeping
+// only p.Fields of each item (all of them if none are set) and p.Metrics of
+// its public_metrics (likewise), and indented unless p.Compact is set.
+func (p searchParams) marshalResults(v interface{}) ([]byte, error) {
+	marshal := func(v interface{}) ([]byte, error) {
+		if p.Compact {
+			return json.Marshal(v)
+		}
+		return json.MarshalIndent(v, "", "  ")
+	}
+	if len(p.Fields) == 0 && len(p.Metrics) == 0 {
+		return marshal(v)
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &doc); err != nil {
+		return nil, err
+	}
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(doc["items"], &items); err != nil {
+		return nil, err
+	}
+	projected := make([]map[string]json.RawMessage, len(items))
+	for i, item := range items {
+		projected[i] = project(item, p.Fields)
+		if metrics, ok := projected[i]["public_metrics"]; ok && len(p.Metrics) > 0 {
+			var m map[string]json.RawMessage
+			if err := json.Unmarshal(metrics, &m); err != nil {
+				return nil, err
+			}
+			if projected[i]["public_metrics"], err = json.Marshal(project(m, p.Metrics)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if doc["items"], err = json.Marshal(projected); err != nil {
+		return nil, err
+	}
+	return marshal(doc)
+}
+
+// project returns the members of obj named in names, or obj itself if names
+// is empty.
+func project(obj map[string]json.RawMessage, names []string) map[string]json.RawMessage {
+	if len(names) == 0 {
+		return obj
+	}
+	kept := make(map[string]json.RawMessage, len(names))
+	for _, name := range names {
+		if value, ok := obj[name]; ok {
+			kept[name] = value
+		}
+	}
+	return kept
+}
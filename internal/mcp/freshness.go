@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"time"
+
+	"masax-mcp/internal/masax"
+)
+
+// Sources of served results, reported in freshness.
+const (
+	sourceLive  = "live"  // Fetched from the API for this request
+	sourceCache = "cache" // From the client's response cache
+	sourceStore = "store" // Stored by an earlier tool call
+)
+
+// freshness tells consumers how old results are and where they were served
+// from, so they can decide whether to refresh them.
+type freshness struct {
+	FetchedAt time.Time `json:"fetched_at"` // When the API returned the results
+	Source    string    `json:"source"`
+}
+
+// annotatedResponse renders a search response with its freshness next to,
+// rather than inside, the items.
+type annotatedResponse struct {
+	*masax.SearchResponse
+	Freshness freshness `json:"freshness"`
+}
+
+// fetchedFreshness describes a response just returned by the client.
+func fetchedFreshness(resp *masax.SearchResponse) freshness {
+	f := freshness{FetchedAt: resp.FetchedAt, Source: sourceLive}
+	if resp.FromCache {
+		f.Source = sourceCache
+	}
+	return f
+}
+
+// storedFreshness describes a response served from the search store.
+func storedFreshness(resp *masax.SearchResponse) freshness {
+	return freshness{FetchedAt: resp.FetchedAt, Source: sourceStore}
+}
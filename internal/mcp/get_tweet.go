@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Sources of a post returned by the get tweet tool.
+const (
+	postSourceStored = "stored" // Found in a search made earlier in this session
+	postSourceAPI    = "api"
+)
+
+// getTweetResult is the JSON payload returned by the get tweet tool.
+type getTweetResult struct {
+	Source    string             `json:"source"`
+	SearchURI string             `json:"search_uri,omitempty"` // Set for stored posts
+	Post      masax.SearchResult `json:"post"`
+}
+
+// handleMasaXGetTweet returns a single post by ID. Posts from searches the
+// server has stored are returned without an API call; otherwise the client's
+// lookup endpoint is used, if one is configured.
+func (s *MCPServer) handleMasaXGetTweet(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, ok := request.Params.Arguments["id"].(string)
+	if !ok || strings.TrimSpace(id) == "" {
+		return mcp.NewToolResultError("Missing or invalid 'id' argument"), nil
+	}
+	id = strings.TrimSpace(id)
+	if err := masax.ValidateTweetID(id); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid 'id' argument: %v", err)), nil
+	}
+
+	log.Printf("Received get tweet request for id: %s", id)
+
+	result := getTweetResult{Source: postSourceStored}
+	post, searchID, found := s.searches.findItem(id)
+	if found {
+		result.Post = post
+		result.SearchURI = searchResultResourcePrefix + searchID
+	} else {
+		fetched, err := s.masaClient.GetByID(ctx, id)
+		if errors.Is(err, masax.ErrNotSupported) {
+			return searchErrorResult(fmt.Errorf("post %s is not in any stored search results; find it with %s instead: %w", id, searchToolName, err)), nil
+		}
+		if err != nil {
+			logSearchError(ctx, err)
+			return searchErrorResult(err), nil
+		}
+		result.Source = postSourceAPI
+		result.Post = *fetched
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal post: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// healthStatus is the JSON payload returned by the health tool.
+type healthStatus struct {
+	Status    string             `json:"status"`
+	LatencyMS int64              `json:"latency_ms"`
+	Circuit   masax.CircuitState `json:"circuit,omitempty"`
+	Retries   *masax.RetryStats  `json:"retries,omitempty"`
+}
+
+// circuitReporter is implemented by searchers with a circuit breaker, such
+// as *masax.Client.
+type circuitReporter interface {
+	CircuitState() masax.CircuitState
+}
+
+// retryReporter is implemented by searchers that count retries, such as
+// *masax.Client.
+type retryReporter interface {
+	RetryStats() masax.RetryStats
+}
+
+// handleMasaXHealth checks that the Masa X API is reachable with the
+// configured credentials, and reports the circuit breaker's state and retry
+// counts if the searcher tracks them.
+func (s *MCPServer) handleMasaXHealth(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var circuit masax.CircuitState
+	if cr, ok := s.masaClient.(circuitReporter); ok {
+		circuit = cr.CircuitState()
+	}
+
+	latency, err := s.masaClient.Ping(ctx)
+	if err != nil {
+		if circuit != "" {
+			return mcp.NewToolResultError(fmt.Sprintf("Masa X API health check failed (%s, circuit %s): %v", masax.ErrorCategory(err), circuit, err)), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("Masa X API health check failed (%s): %v", masax.ErrorCategory(err), err)), nil
+	}
+
+	status := healthStatus{Status: "ok", LatencyMS: latency.Milliseconds(), Circuit: circuit}
+	if rr, ok := s.masaClient.(retryReporter); ok {
+		retries := rr.RetryStats()
+		status.Retries = &retries
+	}
+	jsonData, err := json.Marshal(status)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal health status: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
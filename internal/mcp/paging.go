@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	pageParam       = "page"
+	pageSizeParam   = "page_size"
+	nextTokenParam  = "next_token"
+	defaultPageSize = 25
+	maxPageSize     = 500
+)
+
+// resultPage is the JSON payload for a paged read of stored search results.
+type resultPage struct {
+	Items     []masax.SearchResult `json:"items"`
+	Metadata  masax.SearchMetadata `json:"metadata"`
+	Page      pageInfo             `json:"page"`
+	Freshness freshness            `json:"freshness"`
+}
+
+// pageInfo locates a page within the stored results. Next and Prev are the
+// resource URIs of the neighbouring pages, empty at either end.
+type pageInfo struct {
+	Page       int    `json:"page"`
+	PageSize   int    `json:"page_size"`
+	TotalPages int    `json:"total_pages"`
+	TotalItems int    `json:"total_items"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+}
+
+// livePage is the JSON payload for a page fetched from the API with a
+// next_token. Next is the resource URI of the following page, empty on the
+// last one.
+type livePage struct {
+	Items     []masax.SearchResult `json:"items"`
+	Metadata  masax.SearchMetadata `json:"metadata"`
+	Next      string               `json:"next,omitempty"`
+	Freshness freshness            `json:"freshness"`
+}
+
+// fetchLivePage fetches the page after a stored search's results at
+// nextToken from the API, with the stored search's query and options. A page
+// the API doesn't know, e.g. for an expired token, is reported as
+// server.ErrResourceNotFound.
+func (s *MCPServer) fetchLivePage(ctx context.Context, stored *storedSearch, nextToken string) (*livePage, error) {
+	if err := masax.ValidateNextToken(nextToken); err != nil {
+		return nil, fmt.Errorf("invalid '%s' parameter: %w", nextTokenParam, err)
+	}
+	params := stored.params
+	resp, err := s.masaClient.SearchPage(ctx, params.Query, params.MaxResults, nextToken, params.searchOptions()...)
+	if err != nil {
+		logSearchError(ctx, err)
+		var apiErr *masax.APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusGone) {
+			return nil, fmt.Errorf("no page at %s for search id '%s', which may have expired; run %s again: %w (%v)",
+				nextTokenParam, stored.id, searchToolName, server.ErrResourceNotFound, err)
+		}
+		return nil, fmt.Errorf("failed to fetch page at %s for search id '%s': %w", nextTokenParam, stored.id, err)
+	}
+	page := &livePage{Items: resp.Items, Metadata: resp.Metadata, Freshness: fetchedFreshness(resp)}
+	if token := resp.Metadata.NextToken; token != "" && token != nextToken {
+		page.Next = nextTokenURI(stored.id, token)
+	}
+	return page, nil
+}
+
+// nextTokenURI builds the resource URI for the live page of a stored search
+// at nextToken.
+func nextTokenURI(searchID, nextToken string) string {
+	return fmt.Sprintf("%s%s?%s=%s", searchResultResourcePrefix, searchID, nextTokenParam, url.QueryEscape(nextToken))
+}
+
+// pageArg parses a positive integer resource URI parameter, returning def if
+// it is absent.
+func pageArg(raw, name string, def int) (int, error) {
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid '%s' parameter %q: must be a positive integer", name, raw)
+	}
+	return n, nil
+}
+
+// pageOf returns page (1-based) of the stored search's results, pageSize
+// items per page. A search with no results has a single, empty page.
+func pageOf(stored *storedSearch, page, pageSize int) (*resultPage, error) {
+	items := stored.response.Items
+	totalPages := (len(items) + pageSize - 1) / pageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+	if page > totalPages {
+		return nil, fmt.Errorf("page %d out of range for search id '%s': it has %d page(s) at %s %d", page, stored.id, totalPages, pageSizeParam, pageSize)
+	}
+
+	start := (page - 1) * pageSize
+	end := min(start+pageSize, len(items))
+	info := pageInfo{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+		TotalItems: len(items),
+	}
+	if page < totalPages {
+		info.Next = pageURI(stored.id, page+1, pageSize)
+	}
+	if page > 1 {
+		info.Prev = pageURI(stored.id, page-1, pageSize)
+	}
+	return &resultPage{
+		Items:     append([]masax.SearchResult{}, items[start:end]...),
+		Metadata:  stored.response.Metadata,
+		Page:      info,
+		Freshness: storedFreshness(stored.response),
+	}, nil
+}
+
+// pageURI builds the resource URI for one page of a stored search. The
+// template only matches parameters in this order.
+func pageURI(searchID string, page, pageSize int) string {
+	return fmt.Sprintf("%s%s?%s=%d&%s=%d", searchResultResourcePrefix, searchID, pageParam, page, pageSizeParam, pageSize)
+}
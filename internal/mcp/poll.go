@@ -0,0 +1,146 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// pollReport is the JSON payload returned by the poll tool.
+type pollReport struct {
+	Query     string               `json:"query"`
+	Since     *masax.Watermark     `json:"since,omitempty"` // Absent on the first poll of a query
+	LastSeen  *masax.Watermark     `json:"last_seen,omitempty"`
+	NewCount  int                  `json:"new_count"`
+	PostsSeen int                  `json:"posts_checked"`
+	SearchURI string               `json:"search_uri"`
+	Items     []masax.SearchResult `json:"items"`
+}
+
+// pollMarkers keeps the watermark of the newest post returned by the poll
+// tool for each query, holding at most maxEntries queries and evicting the
+// least recently polled.
+type pollMarkers struct {
+	mu         sync.Mutex
+	maxEntries int
+	seq        uint64
+	entries    map[string]pollMarker
+}
+
+type pollMarker struct {
+	mark masax.Watermark
+	used uint64 // Sequence number of the last poll, for eviction
+}
+
+func newPollMarkers(maxEntries int) *pollMarkers {
+	return &pollMarkers{maxEntries: maxEntries, entries: make(map[string]pollMarker)}
+}
+
+// pollKey identifies a polled query. Queries differing only in case or
+// whitespace share a marker.
+func pollKey(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
+
+// get returns the marker for query, if any.
+func (pm *pollMarkers) get(query string) (masax.Watermark, bool) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	m, ok := pm.entries[pollKey(query)]
+	return m.mark, ok
+}
+
+// set moves the marker for query up to mark, evicting the least recently
+// polled query if full. A marker never moves back, so when concurrent polls
+// of a query finish out of order, the slower one can't re-deliver the posts
+// the faster one saw.
+func (pm *pollMarkers) set(query string, mark masax.Watermark) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.seq++
+	key := pollKey(query)
+	if old, ok := pm.entries[key]; ok {
+		mark = old.mark.Later(mark)
+	}
+	pm.entries[key] = pollMarker{mark: mark, used: pm.seq}
+	if len(pm.entries) > pm.maxEntries {
+		oldestKey, oldest := "", pm.seq
+		for key, m := range pm.entries {
+			if m.used < oldest {
+				oldestKey, oldest = key, m.used
+			}
+		}
+		delete(pm.entries, oldestKey)
+	}
+}
+
+// handleMasaXPoll runs a search sorted by recency and returns only the posts
+// newer than the last ones seen for the query, then moves the query's marker
+// up to the newest post. The marker can be overridden with since_id, or
+// taken from a stored search's newest post with search_id.
+func (s *MCPServer) handleMasaXPoll(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := queryArg(request, "query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	maxResults, err := s.maxResultsArg(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	sinceID, _ := request.Params.Arguments["since_id"].(string)
+	searchID, _ := request.Params.Arguments[searchIDParam].(string)
+	if sinceID != "" && searchID != "" {
+		return mcp.NewToolResultError(fmt.Sprintf("Pass at most one of 'since_id' and '%s'", searchIDParam)), nil
+	}
+
+	mark, seen := s.polls.get(query)
+	switch {
+	case sinceID != "":
+		if err := masax.ValidateTweetID(sinceID); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid 'since_id' argument: %v", err)), nil
+		}
+		mark, seen = masax.Watermark{ID: sinceID}, true
+	case searchID != "":
+		stored, ok := s.searches.get(searchID)
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("No stored results for search id '%s'; run %s again", searchID, searchToolName)), nil
+		}
+		mark, seen = masax.Watermark{}.Advance(stored.response.Items), true
+	}
+
+	log.Printf("Received poll request for query: '%s', max_results: %d", query, maxResults)
+
+	params := searchParams{Query: query, MaxResults: maxResults, SortBy: masax.SortRecency}
+	searchResponse, err := s.search(ctx, params)
+	if err != nil {
+		logSearchError(ctx, err)
+		return searchErrorResult(err), nil
+	}
+	report := pollReport{
+		Query:     query,
+		Items:     masax.NewerThan(searchResponse.Items, mark),
+		PostsSeen: len(searchResponse.Items),
+		SearchURI: searchResultResourcePrefix + s.saveSearch(params, searchResponse),
+	}
+	report.NewCount = len(report.Items)
+	if seen {
+		report.Since = &mark
+	}
+	if next := mark.Advance(searchResponse.Items); !next.IsZero() {
+		report.LastSeen = &next
+		s.polls.set(query, next)
+	}
+
+	jsonData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal poll results: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// progressNotification is the MCP method for progress updates.
+const progressNotification = "notifications/progress"
+
+type progressTokenKey struct{}
+
+// withProgressToken returns ctx carrying the request's progress token, if
+// the client sent one.
+func withProgressToken(ctx context.Context, request mcp.CallToolRequest) context.Context {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressTokenKey{}, request.Params.Meta.ProgressToken)
+}
+
+// withoutProgressToken returns ctx without a progress token, for work that
+// outlives the tool call, whose token is then no longer valid.
+func withoutProgressToken(ctx context.Context) context.Context {
+	return context.WithValue(ctx, progressTokenKey{}, nil)
+}
+
+// progressOptions returns a search option that reports each page fetched
+// as a progress notification, if the tool call asked for progress. The
+// progress value is the page count, which always increases; the message
+// carries the result count.
+func (s *MCPServer) progressOptions(ctx context.Context) []masax.SearchOption {
+	token, ok := ctx.Value(progressTokenKey{}).(mcp.ProgressToken)
+	if !ok || token == nil {
+		return nil
+	}
+	return []masax.SearchOption{masax.WithProgress(func(p masax.Progress) {
+		err := s.SendNotificationToClient(ctx, progressNotification, map[string]any{
+			"progressToken": token,
+			"progress":      p.Pages,
+			"message":       fmt.Sprintf("fetched page %d, %d of %d results so far", p.Pages, p.Results, p.Target),
+		})
+		if err != nil {
+			log.Printf("Failed to send progress notification: %v", err)
+		}
+	})}
+}
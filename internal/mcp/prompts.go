@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultSummaryResults is how many posts the summary prompt includes unless
+// max_results is given.
+const defaultSummaryResults = 20
+
+// handleSummarizePrompt runs a search and returns a prompt asking the LLM to
+// summarize the most engaging posts.
+func (s *MCPServer) handleSummarizePrompt(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	query, err := normalizeQueryArg("query", request.Params.Arguments["query"])
+	if err != nil {
+		return nil, fmt.Errorf("missing 'query' argument")
+	}
+	maxResults := defaultSummaryResults
+	if raw := request.Params.Arguments["max_results"]; raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > s.maxResultsLimit {
+			return nil, fmt.Errorf("invalid 'max_results' argument '%s': must be a whole number between 1 and %d", raw, s.maxResultsLimit)
+		}
+		maxResults = n
+	}
+	loc, err := masax.LoadTimezone(request.Params.Arguments["timezone"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'timezone' argument: %w", err)
+	}
+
+	params := searchParams{Query: query, MaxResults: maxResults, SortBy: masax.SortEngagement}
+	searchResponse, err := s.search(ctx, params)
+	if err != nil {
+		logSearchError(ctx, err)
+		return nil, fmt.Errorf("%s", toolErrorMessage(err))
+	}
+	searchID := s.saveSearch(params, searchResponse)
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Summarize Masa X search results for '%s'", query),
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(summaryPromptText(query, searchID, searchResponse, loc))),
+		},
+	), nil
+}
+
+// summaryPromptText renders the summarization instructions followed by the
+// posts, most engaging first, each with its engagement counts and posting
+// time in loc.
+func summaryPromptText(query, searchID string, resp *masax.SearchResponse, loc *time.Location) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Summarize what people on X are saying about \"%s\", based on the %d posts below, ordered by engagement.\n", query, len(resp.Items))
+	b.WriteString("Identify the main themes and notable opinions, and use the engagement counts to say which views resonate most. ")
+	b.WriteString("Cite posts by their number when making specific claims.\n")
+	fmt.Fprintf(&b, "The full results are available at %s%s.\n\n", searchResultResourcePrefix, searchID)
+
+	if len(resp.Items) == 0 {
+		b.WriteString("(The search returned no posts.)\n")
+		return b.String()
+	}
+	for i, item := range resp.Items {
+		m := item.PublicMetrics
+		fmt.Fprintf(&b, "%d. [author %s, %s] likes %d, retweets %d, replies %d, quotes %d\n%s\n",
+			i+1, item.AuthorID, masax.FormatTimestamp(item.CreatedAt, loc), m.LikeCount, m.RetweetCount, m.ReplyCount, m.QuoteCount, item.Text)
+		if item.URL != "" {
+			fmt.Fprintf(&b, "%s\n", item.URL)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
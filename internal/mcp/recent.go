@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// searchSummary describes a stored search in the recent searches listing.
+type searchSummary struct {
+	SearchID    string    `json:"search_id"`
+	Query       string    `json:"query"`
+	ResultCount int       `json:"result_count"`
+	StoredAt    time.Time `json:"stored_at"`
+	URI         string    `json:"uri"`
+}
+
+// saveSearch stores a response and lists its result resource, so clients
+// discover it through resources/list; the resource of the search evicted to
+// make room is unlisted.
+func (s *MCPServer) saveSearch(params searchParams, resp *masax.SearchResponse) string {
+	searchID, evicted := s.searches.save(params, resp)
+	if evicted != "" {
+		s.RemoveResource(searchResultResourcePrefix + evicted)
+	}
+
+	resource := mcp.NewResource(
+		searchResultResourcePrefix+searchID,
+		fmt.Sprintf("MasaX Search: %s", params.Query),
+		mcp.WithResourceDescription(fmt.Sprintf("Results of the Masa X search for '%s' (%d posts).", params.Query, len(resp.Items))),
+		mcp.WithMIMEType(jsonMimeType),
+	)
+	s.AddResource(resource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		// Listed URIs match exactly, bypassing the template, so supply the
+		// search_id the template would have extracted
+		request.Params.Arguments = map[string]interface{}{searchIDParam: []string{searchID}}
+		return s.handleReadSearchResult(ctx, request)
+	})
+	return searchID
+}
+
+// handleListSearches serves the directory of stored searches, most recently
+// stored first.
+func (s *MCPServer) handleListSearches(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	stored := s.searches.list()
+	summaries := make([]searchSummary, 0, len(stored))
+	for _, search := range stored {
+		summaries = append(summaries, searchSummary{
+			SearchID:    search.id,
+			Query:       search.params.Query,
+			ResultCount: len(search.response.Items),
+			StoredAt:    search.storedAt,
+			URI:         searchResultResourcePrefix + search.id,
+		})
+	}
+
+	jsonData, err := json.MarshalIndent(map[string]interface{}{"searches": summaries}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal search listing: %w", err)
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: jsonMimeType,
+			Text:     string(jsonData),
+		},
+	}, nil
+}
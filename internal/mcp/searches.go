@@ -0,0 +1,201 @@
+package mcp
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"masax-mcp/internal/masax"
+)
+
+const defaultMaxStoredSearches = 100
+
+// searchParams are the tool arguments needed to reproduce a search.
+type searchParams struct {
+	Query       string
+	MaxResults  int
+	StartTime   time.Time          `json:",omitempty"`
+	EndTime     time.Time          `json:",omitempty"`
+	SortBy      masax.SortOrder    `json:",omitempty"`
+	MinLikes    int                `json:",omitempty"`
+	MinRetweets int                `json:",omitempty"`
+	Lang        string             `json:",omitempty"`
+	Place       string             `json:",omitempty"`
+	Geo         *masax.PointRadius `json:",omitempty"`
+	Dedupe      bool               `json:",omitempty"`
+	Sentiment   bool               `json:",omitempty"`
+	Entities    bool               `json:",omitempty"`
+	Fields      []string           `json:",omitempty"` // Result fields to render; nil means all
+	Metrics     []string           `json:",omitempty"` // Public metrics to render; nil means all
+	Compact     bool               `json:",omitempty"` // Render JSON without indentation
+
+	ExcludeRetweets bool `json:",omitempty"`
+	ExcludeReplies  bool `json:",omitempty"`
+
+	ExcludeTerms      []string `json:",omitempty"` // Drop results whose text contains any of these
+	ExcludeWholeWords bool     `json:",omitempty"`
+	VerifiedOnly      bool     `json:",omitempty"`
+
+	ExpandSynonyms bool           `json:",omitempty"`
+	Synonyms       masax.Synonyms `json:",omitempty"` // Added to the server's synonyms
+
+	DryRun bool `json:"-"` // Not a different search; dry runs aren't stored
+}
+
+// searchID derives a stable, URI-safe identifier from the search parameters,
+// so identical searches map to the same resource URI.
+func (p searchParams) searchID() string {
+	encoded, _ := json.Marshal(p) // Plain values; marshaling can't fail
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:16])
+}
+
+// searchOptions converts the optional parameters into client search options.
+func (p searchParams) searchOptions() []masax.SearchOption {
+	var opts []masax.SearchOption
+	if !p.StartTime.IsZero() || !p.EndTime.IsZero() {
+		opts = append(opts, masax.WithTimeRange(p.StartTime, p.EndTime))
+	}
+	if p.SortBy != "" {
+		opts = append(opts, masax.WithSortBy(p.SortBy))
+	}
+	if p.Lang != "" {
+		opts = append(opts, masax.WithLanguage(p.Lang))
+	}
+	if p.Place != "" {
+		opts = append(opts, masax.WithPlace(p.Place))
+	}
+	if p.Geo != nil {
+		opts = append(opts, masax.WithPointRadius(*p.Geo))
+	}
+	if p.ExcludeRetweets {
+		opts = append(opts, masax.WithExcludeRetweets())
+	}
+	if p.ExcludeReplies {
+		opts = append(opts, masax.WithExcludeReplies())
+	}
+	if p.ExpandSynonyms || len(p.Synonyms) > 0 {
+		opts = append(opts, masax.WithQueryExpansion(p.Synonyms))
+	}
+	if p.Dedupe {
+		opts = append(opts, masax.WithDedupe(nil))
+	}
+	if p.Sentiment {
+		opts = append(opts, masax.WithSentiment(nil))
+	}
+	if p.Entities {
+		opts = append(opts, masax.WithEntities())
+	}
+	if p.DryRun {
+		opts = append(opts, masax.WithDryRun())
+	}
+	if p.VerifiedOnly {
+		opts = append(opts, masax.WithItemFilter(masax.VerifiedOnly()))
+	}
+	if len(p.ExcludeTerms) > 0 {
+		opts = append(opts, masax.WithItemFilter(masax.ExcludeTerms(p.ExcludeTerms, p.ExcludeWholeWords)))
+	}
+	if p.MinLikes > 0 || p.MinRetweets > 0 {
+		opts = append(opts, masax.WithItemFilter(masax.MinEngagement(p.MinLikes, p.MinRetweets)))
+	}
+	return opts
+}
+
+// storedSearch is a search result as returned by the search tool.
+type storedSearch struct {
+	id       string
+	params   searchParams
+	response *masax.SearchResponse
+	storedAt time.Time
+}
+
+// searchStore keeps the responses produced by the search tool so resource
+// reads return exactly what the tool returned. It holds at most maxEntries
+// searches, evicting the least recently used.
+type searchStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // Front is most recently used
+	entries    map[string]*list.Element
+}
+
+func newSearchStore(maxEntries int) *searchStore {
+	return &searchStore{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// save stores resp under the search_id for params and returns that ID, along
+// with the ID of the search evicted to make room, if any.
+func (st *searchStore) save(params searchParams, resp *masax.SearchResponse) (id, evicted string) {
+	id = params.searchID()
+	entry := &storedSearch{id: id, params: params, response: resp, storedAt: time.Now().UTC()}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if elem, ok := st.entries[id]; ok {
+		elem.Value = entry
+		st.order.MoveToFront(elem)
+		return id, ""
+	}
+	st.entries[id] = st.order.PushFront(entry)
+	if st.order.Len() > st.maxEntries {
+		oldest := st.order.Back()
+		st.order.Remove(oldest)
+		evicted = oldest.Value.(*storedSearch).id
+		delete(st.entries, evicted)
+	}
+	return id, evicted
+}
+
+// get returns the search stored under id.
+func (st *searchStore) get(id string) (*storedSearch, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	elem, ok := st.entries[id]
+	if !ok {
+		return nil, false
+	}
+	st.order.MoveToFront(elem)
+	return elem.Value.(*storedSearch), true
+}
+
+// list returns the stored searches, most recently stored first. Unlike get,
+// it doesn't count as a use.
+func (st *searchStore) list() []*storedSearch {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	searches := make([]*storedSearch, 0, st.order.Len())
+	for elem := st.order.Front(); elem != nil; elem = elem.Next() {
+		searches = append(searches, elem.Value.(*storedSearch))
+	}
+	sort.Slice(searches, func(i, j int) bool {
+		if !searches[i].storedAt.Equal(searches[j].storedAt) {
+			return searches[i].storedAt.After(searches[j].storedAt)
+		}
+		return searches[i].id < searches[j].id
+	})
+	return searches
+}
+
+// findItem returns the first stored result with the given post ID and the
+// search it belongs to, checking the most recently used searches first.
+func (st *searchStore) findItem(postID string) (masax.SearchResult, string, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for elem := st.order.Front(); elem != nil; elem = elem.Next() {
+		stored := elem.Value.(*storedSearch)
+		for _, item := range stored.response.Items {
+			if item.ID == postID {
+				return item, stored.id, true
+			}
+		}
+	}
+	return masax.SearchResult{}, "", false
+}
@@ -1,10 +1,15 @@
 package mcp
 
 import (
+	"bytes"
 	"context"
 	"encoding/json" // Import encoding/json
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"strings"
+	"time"
 
 	"masax-mcp/internal/masax" // Import masax client package
 
@@ -17,28 +22,130 @@ const (
 	serverName                 = "MasaX_MCP_Server"
 	serverVersion              = "0.1.0"
 	searchToolName             = "masa_x_search"
+	searchByUserToolName       = "masa_x_search_by_user"
+	healthToolName             = "masa_x_health"
+	searchBatchToolName        = "masa_x_search_batch"
+	trendsToolName             = "masa_x_trends"
+	topAuthorsToolName         = "masa_x_top_authors"
+	topToolName                = "masa_x_top"
+	compareToolName            = "masa_x_compare"
+	getTweetToolName           = "masa_x_get_tweet"
+	usageToolName              = "masa_x_usage"
+	exportToolName             = "masa_x_export"
+	searchWebhookToolName      = "masa_x_search_webhook"
+	pollToolName               = "masa_x_poll"
+	searchWindowsToolName      = "masa_x_search_windows"
+	debugRequestToolName       = "masa_x_debug_request"
+	summarizePromptName        = "summarize_masax_search"
 	searchResultResourcePrefix = "masax://search/results/"
+	searchCSVResourcePrefix    = "masax://search/csv/"
+	searchNDJSONResourcePrefix = "masax://search/ndjson/"
+	searchListResourceURI      = "masax://search/results"
 	searchIDParam              = "search_id" // Consistent param name
 	jsonMimeType               = "application/json"
+	csvMimeType                = "text/csv"
+	ndjsonMimeType             = "application/x-ndjson"
 )
 
+// defaultMaxResults is the max_results applied when a tool call omits it.
+const defaultMaxResults = 20
+
+// maxExcludeTerms bounds the search tool's exclude_terms argument.
+const maxExcludeTerms = 50
+
 // MCPServer wraps the mcp-go server implementation.
 type MCPServer struct {
 	*server.MCPServer
-	masaClient *masax.Client // Add Masa X client
+	masaClient masax.Searcher // Masa X client, or a fake in tests
+	searches   *searchStore
+	polls      *pollMarkers
+	lifecycle  lifecycle
+
+	maxStoredSearches int
+	maxResultsLimit   int
+	pageSize          int // Largest max_results fetched in a single request
+	defaultMaxResults int // Applied when a tool call omits max_results
+
+	tools        toolConfig // Disabled tools and description overrides
+	toolNames    []string   // All tools defined, registered or not
+	resultBudget int        // Approximate byte limit on results embedded in a tool response
+	exportDir    string     // Where the export tool writes files; empty disables it
+	webhookHosts []string   // Allowed webhook callback hosts; empty disables webhooks
+}
+
+// ServerOption defines a functional option for configuring the MCPServer.
+type ServerOption func(*MCPServer)
+
+// WithMaxStoredSearches limits how many search results are kept for resource
+// reads (100 by default). The least recently used results are evicted first.
+func WithMaxStoredSearches(n int) ServerOption {
+	return func(s *MCPServer) {
+		if n > 0 {
+			s.maxStoredSearches = n
+		}
+	}
+}
+
+// WithMaxResultsLimit sets the largest max_results the tools accept
+// (masax.DefaultMaxResultsLimit by default). Searches asking for more than
+// the page size are fetched a page at a time; see WithPageSize.
+func WithMaxResultsLimit(n int) ServerOption {
+	return func(s *MCPServer) {
+		if n > 0 {
+			s.maxResultsLimit = n
+		}
+	}
+}
+
+// WithPageSize sets the largest max_results the client fetches in a single
+// request (masax.DefaultMaxResultsLimit by default), which should match the
+// limit configured on the client. Tool searches asking for more go through
+// SearchAll, sending a progress notification for each page if the tool call
+// carries a progress token.
+func WithPageSize(n int) ServerOption {
+	return func(s *MCPServer) {
+		if n > 0 {
+			s.pageSize = n
+		}
+	}
 }
 
-// NewServer creates and configures a new MCP server instance, accepting the masax client.
-func NewServer(client *masax.Client) (*MCPServer, error) {
+// WithDefaultMaxResults sets the max_results applied when a tool call omits
+// it (20 by default), capped at the max_results limit, so unqualified calls
+// don't return huge payloads. An explicit max_results of 0 still leaves the
+// count to the API, as does a default of 0.
+func WithDefaultMaxResults(n int) ServerOption {
+	return func(s *MCPServer) {
+		if n >= 0 {
+			s.defaultMaxResults = n
+		}
+	}
+}
+
+// NewServer creates and configures a new MCP server instance, accepting the
+// masax client or any other masax.Searcher.
+func NewServer(client masax.Searcher, options ...ServerOption) (*MCPServer, error) {
 	if client == nil {
 		return nil, fmt.Errorf("masax client cannot be nil")
 	}
-	s := server.NewMCPServer(serverName, serverVersion)
+	s := server.NewMCPServer(serverName, serverVersion, server.WithResourceCapabilities(false, true))
 
 	mcpServer := &MCPServer{
 		MCPServer:  s,
 		masaClient: client, // Store the client
+
+		maxStoredSearches: defaultMaxStoredSearches,
+		maxResultsLimit:   masax.DefaultMaxResultsLimit,
+		pageSize:          masax.DefaultMaxResultsLimit,
+		defaultMaxResults: defaultMaxResults,
+		resultBudget:      defaultResultBudget,
+	}
+	for _, opt := range options {
+		opt(mcpServer)
 	}
+	mcpServer.defaultMaxResults = min(mcpServer.defaultMaxResults, mcpServer.maxResultsLimit)
+	mcpServer.searches = newSearchStore(mcpServer.maxStoredSearches)
+	mcpServer.polls = newPollMarkers(mcpServer.maxStoredSearches)
 
 	if err := mcpServer.registerComponents(); err != nil {
 		return nil, fmt.Errorf("failed to register MCP components: %w", err)
@@ -60,104 +167,771 @@ func (s *MCPServer) registerComponents() error {
 		),
 		// Add max_results argument (using WithNumber)
 		mcp.WithNumber("max_results",
-			mcp.Description("Maximum number of search results to return (optional)"),
-			// Add constraints if needed, e.g., mcp.Min(1)
+			mcp.Description(fmt.Sprintf("Maximum number of search results to return, %s", s.maxResultsUsage())),
+			mcp.Min(0),
+			mcp.Max(float64(s.maxResultsLimit)),
+			mcp.DefaultNumber(float64(s.defaultMaxResults)),
+		),
+		mcp.WithString("start_time",
+			mcp.Description("Only return posts created at or after this time, as an RFC3339 timestamp or YYYY-MM-DD date (optional)"),
+		),
+		mcp.WithString("end_time",
+			mcp.Description("Only return posts created before this time, as an RFC3339 timestamp or YYYY-MM-DD date (optional)"),
+		),
+		mcp.WithString("sort_by",
+			mcp.Description("Result ordering (optional). 'recency' and 'relevancy' are applied by the API; 'engagement' reorders the returned results by likes, retweets, replies and quotes, summed with the server's engagement weights."),
+			mcp.Enum(string(masax.SortRecency), string(masax.SortRelevancy), string(masax.SortEngagement)),
+		),
+		mcp.WithString("lang",
+			mcp.Description("Only return posts in this language, as a two-letter ISO 639-1 code such as 'en' or 'es' (optional). Applied server-side by the Masa X API."),
+			mcp.Enum(masax.LanguageCodes()...),
+		),
+		mcp.WithString("place",
+			mcp.Description("Only return posts tagged with this place, e.g. 'new york city' (optional). Sent as the place: query operator; only geo-tagged posts can match, and the filter has no effect if the API doesn't support it."),
+		),
+		mcp.WithString("geo",
+			mcp.Description(fmt.Sprintf("Only return posts tagged within a radius of a point, as 'lat,lon,radius_km' e.g. '40.7128,-74.0060,10' (optional, radius at most %d km). Sent as the point_radius: query operator, with the same caveats as 'place'.", masax.MaxRadiusKm)),
+		),
+		mcp.WithNumber("min_likes",
+			mcp.Description("Only return posts with at least this many likes (optional)"),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("min_retweets",
+			mcp.Description("Only return posts with at least this many retweets (optional)"),
+			mcp.Min(0),
+		),
+		mcp.WithBoolean("include_retweets",
+			mcp.Description("Whether to include retweets (optional, default true). When false, retweets are excluded by the API and any post starting with 'RT @' is dropped."),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithBoolean("include_replies",
+			mcp.Description("Whether to include replies (optional, default true). When false, replies are excluded by the API and any post starting with an @mention is dropped."),
+			mcp.DefaultBool(true),
+		),
+		mcp.WithBoolean("verified_only",
+			mcp.Description("Only return posts whose author has a verified (blue check) account (optional, default false). Relies on the API reporting author_verified for each post; when it doesn't, as the Masa X API currently doesn't, no posts are returned."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithArray("exclude_terms",
+			mcp.Description(fmt.Sprintf("Drop posts whose text contains any of these terms, ignoring case, e.g. ['giveaway', 'airdrop'] (optional, at most %d). Applied to the returned posts, not sent to the API; more pages are fetched to make up for dropped posts.", maxExcludeTerms)),
+			mcp.Items(map[string]interface{}{"type": "string", "minLength": 1}),
+			mcp.MaxItems(maxExcludeTerms),
+		),
+		mcp.WithBoolean("exclude_whole_words",
+			mcp.Description("Match exclude_terms only as whole words, so 'ape' excludes 'ape NFT' but not 'grape' (optional, default false)"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("expand_synonyms",
+			mcp.Description("Broaden the query with the server's configured synonyms, replacing each term that has synonyms with an OR of it and its alternatives, e.g. AI becomes (AI OR \"artificial intelligence\") (optional, default false)"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("synonyms",
+			mcp.Description("Extra synonyms to expand the query with, as term=alternative|alternative entries separated by semicolons, e.g. 'AI=artificial intelligence|machine learning;BTC=bitcoin' (optional). Implies expand_synonyms."),
+		),
+		mcp.WithBoolean("dedupe",
+			mcp.Description("Drop near-identical posts (same text ignoring case, links and mentions), keeping the most engaged copy (optional, default false)"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("sentiment",
+			mcp.Description("Attach a lexicon-based sentiment label and score (-1 to 1) to each post (optional, default false)"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("entities",
+			mcp.Description("Attach the hashtags, mentions and URLs parsed from each post's text (optional, default false)"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("fields",
+			mcp.Description(fmt.Sprintf("Comma-separated result fields to return, e.g. 'text,public_metrics' (optional, default all). 'id' is always included. Valid fields: %s", strings.Join(sortedKeys(resultFields), ", "))),
+		),
+		mcp.WithString("metrics",
+			mcp.Description(fmt.Sprintf("Comma-separated public metrics to return in each result's public_metrics, e.g. 'like_count' (optional, default all). Valid metrics: %s", strings.Join(sortedKeys(metricFields), ", "))),
+		),
+		mcp.WithBoolean("compact",
+			mcp.Description("Return compact JSON without indentation, which is smaller but harder to read (optional, default false)"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Validate the arguments and return the request that would be sent, without calling the API or using quota (optional, default false)"),
+			mcp.DefaultBool(false),
+		),
+	)
+
+	s.addTool(searchTool, s.handleMasaXSearch)
+
+	// Define the author-scoped search tool
+	searchByUserTool := mcp.NewTool(
+		searchByUserToolName,
+		mcp.WithDescription("Searches recent posts from a specific X account using the Masa X API."),
+		mcp.WithString(
+			"username",
+			mcp.Description("The X username to search, with or without a leading @."),
+			mcp.Required(),
+			mcp.Pattern(masax.UsernamePattern),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description(fmt.Sprintf("Maximum number of search results to return, %s", s.maxResultsUsage())),
+			mcp.Min(0),
+			mcp.Max(float64(s.maxResultsLimit)),
+			mcp.DefaultNumber(float64(s.defaultMaxResults)),
+		),
+	)
+
+	s.addTool(searchByUserTool, s.handleMasaXSearchByUser)
+
+	// Define the multi-query search tool
+	searchBatchTool := mcp.NewTool(
+		searchBatchToolName,
+		mcp.WithDescription("Runs several Masa X searches in parallel and returns the results for each query. A failing query doesn't prevent results for the others."),
+		mcp.WithArray("queries",
+			mcp.Description(fmt.Sprintf("The search query strings, at most %d.", maxBatchQueries)),
+			mcp.Items(map[string]interface{}{"type": "string", "minLength": 1}),
+			mcp.Required(),
+			mcp.MinItems(1),
+			mcp.MaxItems(maxBatchQueries),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description(fmt.Sprintf("Maximum number of search results to return per query, %s", s.maxResultsUsage())),
+			mcp.Min(0),
+			mcp.Max(float64(s.maxResultsLimit)),
+			mcp.DefaultNumber(float64(s.defaultMaxResults)),
+		),
+	)
+
+	s.addTool(searchBatchTool, s.handleMasaXSearchBatch)
+
+	// Define the multi-window search tool
+	searchWindowsTool := mcp.NewTool(
+		searchWindowsToolName,
+		mcp.WithDescription("Runs the same Masa X search over several time windows in parallel and returns the results grouped by window, with per-window counts, to show how a topic evolved across days or weeks. A failing window doesn't prevent results for the others."),
+		mcp.WithString(
+			"query",
+			mcp.Description("The search query string."),
+			mcp.Required(),
+		),
+		mcp.WithArray("windows",
+			mcp.Description(fmt.Sprintf("The time windows to search, at most %d, each with a 'start' (inclusive) and 'end' (exclusive) as an RFC3339 timestamp or YYYY-MM-DD date, e.g. [{\"start\": \"2024-05-01\", \"end\": \"2024-05-08\"}].", maxTimeWindows)),
+			mcp.Items(map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"start": map[string]interface{}{"type": "string"},
+					"end":   map[string]interface{}{"type": "string"},
+				},
+				"required": []string{"start", "end"},
+			}),
+			mcp.Required(),
+			mcp.MinItems(1),
+			mcp.MaxItems(maxTimeWindows),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description(fmt.Sprintf("Maximum number of search results to return per window, %s", s.maxResultsUsage())),
+			mcp.Min(0),
+			mcp.Max(float64(s.maxResultsLimit)),
+			mcp.DefaultNumber(float64(s.defaultMaxResults)),
+		),
+	)
+
+	s.addTool(searchWindowsTool, s.handleMasaXSearchWindows)
+
+	// Define the trend aggregation tool
+	trendsTool := mcp.NewTool(
+		trendsToolName,
+		mcp.WithDescription("Runs a Masa X search and returns the most frequent hashtags, mentions and URLs in the results, with how many posts mention each and their total engagement."),
+		mcp.WithString(
+			"query",
+			mcp.Description("The search query string."),
+			mcp.Required(),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description(fmt.Sprintf("Maximum number of posts to analyze, %s", s.maxResultsUsage())),
+			mcp.Min(0),
+			mcp.Max(float64(s.maxResultsLimit)),
+			mcp.DefaultNumber(float64(s.defaultMaxResults)),
+		),
+		mcp.WithNumber("top_n",
+			mcp.Description(fmt.Sprintf("Number of entries to return per entity type (optional, default %d)", defaultTrendsTopN)),
+			mcp.Min(1),
+			mcp.DefaultNumber(defaultTrendsTopN),
+		),
+	)
+
+	s.addTool(trendsTool, s.handleMasaXTrends)
+
+	// Define the author aggregation tool
+	topAuthorsTool := mcp.NewTool(
+		topAuthorsToolName,
+		mcp.WithDescription("Runs a Masa X search and groups the results by author, returning each author's post count and summed public metrics, ordered by total engagement."),
+		mcp.WithString(
+			"query",
+			mcp.Description("The search query string."),
+			mcp.Required(),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description(fmt.Sprintf("Maximum number of posts to analyze, %s", s.maxResultsUsage())),
+			mcp.Min(0),
+			mcp.Max(float64(s.maxResultsLimit)),
+			mcp.DefaultNumber(float64(s.defaultMaxResults)),
+		),
+		mcp.WithNumber("top_n",
+			mcp.Description(fmt.Sprintf("Number of authors to return (optional, default %d)", defaultTopAuthors)),
+			mcp.Min(1),
+			mcp.DefaultNumber(defaultTopAuthors),
+		),
+	)
+
+	s.addTool(topAuthorsTool, s.handleMasaXTopAuthors)
+
+	// Define the top post tool
+	topTool := mcp.NewTool(
+		topToolName,
+		mcp.WithDescription("Runs a Masa X search and returns only the single post with the highest engagement: the weighted sum of its likes, retweets, replies and quotes, using the server's configured weights unless set."),
+		mcp.WithString(
+			"query",
+			mcp.Description("The search query string."),
+			mcp.Required(),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description(fmt.Sprintf("Maximum number of posts to consider, %s", s.maxResultsUsage())),
+			mcp.Min(0),
+			mcp.Max(float64(s.maxResultsLimit)),
+			mcp.DefaultNumber(float64(s.defaultMaxResults)),
+		),
+		mcp.WithNumber("like_weight",
+			mcp.Description("Weight of each like (optional, defaults to the server's weight, normally 1)"),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("retweet_weight",
+			mcp.Description("Weight of each retweet (optional, defaults to the server's weight, normally 1)"),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("reply_weight",
+			mcp.Description("Weight of each reply (optional, defaults to the server's weight, normally 1)"),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("quote_weight",
+			mcp.Description("Weight of each quote (optional, defaults to the server's weight, normally 1)"),
+			mcp.Min(0),
+		),
+	)
+
+	s.addTool(topTool, s.handleMasaXTop)
+
+	// Define the engagement comparison tool
+	compareTool := mcp.NewTool(
+		compareToolName,
+		mcp.WithDescription("Runs two Masa X searches and compares their engagement side by side: result counts, total likes, retweets, replies and quotes, and averages per post. 'leader' names the query with the higher average engagement per post."),
+		mcp.WithString("query_a",
+			mcp.Description("The first search query string."),
+			mcp.Required(),
+		),
+		mcp.WithString("query_b",
+			mcp.Description("The second search query string."),
+			mcp.Required(),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description(fmt.Sprintf("Maximum number of posts to analyze per query, %s", s.maxResultsUsage())),
+			mcp.Min(0),
+			mcp.Max(float64(s.maxResultsLimit)),
+			mcp.DefaultNumber(float64(s.defaultMaxResults)),
 		),
 	)
 
-	s.AddTool(searchTool, s.handleMasaXSearch)
+	s.addTool(compareTool, s.handleMasaXCompare)
+
+	// Define the incremental polling tool
+	pollTool := mcp.NewTool(
+		pollToolName,
+		mcp.WithDescription("Polls a Masa X search for new posts: runs it sorted by recency and returns only posts newer than the last ones this tool returned for the same query, then remembers the newest. The first poll of a query returns every post. Call it on an interval to monitor a topic without reprocessing old posts."),
+		mcp.WithString(
+			"query",
+			mcp.Description("The search query string. Queries differing only in case or whitespace share their last-seen marker."),
+			mcp.Required(),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description(fmt.Sprintf("Maximum number of recent posts to check, %s", s.maxResultsUsage())),
+			mcp.Min(0),
+			mcp.Max(float64(s.maxResultsLimit)),
+			mcp.DefaultNumber(float64(s.defaultMaxResults)),
+		),
+		mcp.WithString("since_id",
+			mcp.Description("Return only posts newer than this post ID, e.g. last_seen.id from an earlier poll, instead of the remembered marker (optional)."),
+			mcp.Pattern(masax.TweetIDPattern),
+		),
+		mcp.WithString(searchIDParam,
+			mcp.Description("Return only posts newer than the newest post of this earlier search, instead of the remembered marker (optional)."),
+		),
+	)
+
+	s.addTool(pollTool, s.handleMasaXPoll)
+
+	// Define the single post lookup tool
+	getTweetTool := mcp.NewTool(
+		getTweetToolName,
+		mcp.WithDescription("Returns a single X post by ID. Posts from earlier search results are returned directly; others need an API lookup endpoint, which the Masa X API may not offer."),
+		mcp.WithString(
+			"id",
+			mcp.Description("The numeric post ID."),
+			mcp.Required(),
+			mcp.Pattern(masax.TweetIDPattern),
+		),
+	)
+
+	s.addTool(getTweetTool, s.handleMasaXGetTweet)
+
+	// Define the quota status tool
+	usageTool := mcp.NewTool(
+		usageToolName,
+		mcp.WithDescription("Reports the Masa X API quota (limit, remaining calls and reset time) from the rate-limit headers of the most recent API response. Makes no API call."),
+	)
+
+	s.addTool(usageTool, s.handleMasaXUsage)
+
+	// Define the file export tool, offered only with an export directory
+	exportTool := mcp.NewTool(
+		exportToolName,
+		mcp.WithDescription("Writes the stored results of an earlier search to a JSON or CSV file on the server and returns its path, for result sets too large to handle inline."),
+		mcp.WithString(searchIDParam,
+			mcp.Description("The search_id from a search result's resource URI."),
+			mcp.Required(),
+		),
+		mcp.WithString("format",
+			mcp.Description("The file format (optional, default 'json')."),
+			mcp.Enum("json", "csv"),
+			mcp.DefaultString("json"),
+		),
+	)
+
+	if s.exportDir != "" {
+		s.addTool(exportTool, s.handleMasaXExport)
+	} else {
+		s.toolNames = append(s.toolNames, exportToolName) // Still a known name for the tool config
+	}
+
+	// Define the webhook search tool, offered only with a callback allowlist
+	searchWebhookTool := mcp.NewTool(
+		searchWebhookToolName,
+		mcp.WithDescription("Starts a Masa X search in the background and returns a delivery ID at once. The results, or the error, are POSTed as JSON to the callback URL, retrying failed deliveries. Only allowlisted callback hosts are accepted."),
+		mcp.WithString(
+			"query",
+			mcp.Description("The search query string."),
+			mcp.Required(),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description(fmt.Sprintf("Maximum number of search results to return, %s", s.maxResultsUsage())),
+			mcp.Min(0),
+			mcp.Max(float64(s.maxResultsLimit)),
+			mcp.DefaultNumber(float64(s.defaultMaxResults)),
+		),
+		mcp.WithString("callback_url",
+			mcp.Description("The http or https URL to POST the results to. Its host must be on the server's webhook allowlist."),
+			mcp.Required(),
+		),
+		mcp.WithString("idempotency_key",
+			mcp.Description("A key identifying this delivery, sent unchanged as the Idempotency-Key header of every attempt so the receiver can drop duplicates from retries (optional, defaults to the delivery ID). Use the same key when re-requesting the same logical delivery."),
+			mcp.Pattern(idempotencyKeyPattern.String()),
+		),
+	)
 
-	// Define the Masa X Search Result Resource (dynamic)
-	searchResultResource := mcp.NewResource(
-		searchResultResourcePrefix+"{"+searchIDParam+"}",
+	if len(s.webhookHosts) > 0 {
+		s.addTool(searchWebhookTool, s.handleMasaXSearchWebhook)
+	} else {
+		s.toolNames = append(s.toolNames, searchWebhookToolName) // Still a known name for the tool config
+	}
+
+	// Define the request inspection tool
+	debugRequestTool := mcp.NewTool(
+		debugRequestToolName,
+		mcp.WithDescription("Returns the exact HTTP request a search would send to the Masa X API (method, URL, headers with credentials redacted, and body) without sending it or using quota, to check the server's configuration."),
+		mcp.WithString(
+			"query",
+			mcp.Description("The search query string."),
+			mcp.Required(),
+		),
+		mcp.WithNumber("max_results",
+			mcp.Description(fmt.Sprintf("Maximum number of search results to request, %s", s.maxResultsUsage())),
+			mcp.Min(0),
+			mcp.Max(float64(s.maxResultsLimit)),
+			mcp.DefaultNumber(float64(s.defaultMaxResults)),
+		),
+	)
+
+	s.addTool(debugRequestTool, s.handleMasaXDebugRequest)
+
+	// Define the connectivity check tool
+	healthTool := mcp.NewTool(
+		healthToolName,
+		mcp.WithDescription("Checks that the Masa X API is reachable with the server's credentials and reports the round-trip latency, circuit breaker state, and cumulative retries and exhausted-retry failures."),
+	)
+
+	s.addTool(healthTool, s.handleMasaXHealth)
+
+	// Define the summarization prompt
+	summarizePrompt := mcp.NewPrompt(
+		summarizePromptName,
+		mcp.WithPromptDescription("Runs a Masa X search and asks for a summary of the most engaging posts."),
+		mcp.WithArgument("query",
+			mcp.ArgumentDescription("The search query string."),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("max_results",
+			mcp.ArgumentDescription(fmt.Sprintf("Number of posts to include, 1-%d (default %d).", s.maxResultsLimit, defaultSummaryResults)),
+		),
+		mcp.WithArgument("timezone",
+			mcp.ArgumentDescription("IANA time zone to show posting times in, e.g. 'America/New_York' (default UTC)."),
+		),
+	)
+
+	s.AddPrompt(summarizePrompt, s.handleSummarizePrompt)
+
+	// Define the Masa X Search Result Resource (dynamic). It is registered as a
+	// template so URIs carrying any search_id are routed to the handler.
+	searchResultTemplate := mcp.NewResourceTemplate(
+		searchResultResourcePrefix+"{"+searchIDParam+"}{?"+pageParam+","+pageSizeParam+","+nextTokenParam+"}",
 		"MasaX Search Result",
-		mcp.WithResourceDescription("Represents the results of a specific Masa X API search."),
+		mcp.WithTemplateDescription(fmt.Sprintf("Represents the results of a specific Masa X API search. Add ?page=N (and optionally &page_size=M, default %d, max %d, after page) to read one page at a time, with next and prev page URIs. Or add ?next_token=T, with the next_token from the results' metadata, to fetch the following page from the live API, with the next page's URI. Every read includes a freshness object giving when the results were fetched (fetched_at) and where they were served from (source: live, cache or store).", defaultPageSize, maxPageSize)),
+		mcp.WithTemplateMIMEType(jsonMimeType),
+	)
+
+	s.AddResourceTemplate(searchResultTemplate, s.handleReadSearchResult)
+
+	// The same stored results rendered as CSV for spreadsheets
+	searchCSVTemplate := mcp.NewResourceTemplate(
+		searchCSVResourcePrefix+"{"+searchIDParam+"}",
+		"MasaX Search Result (CSV)",
+		mcp.WithTemplateDescription("The results of a specific Masa X API search as CSV with columns id, author_id, created_at, text, like_count, retweet_count, reply_count, quote_count, url."),
+		mcp.WithTemplateMIMEType(csvMimeType),
+	)
+
+	s.AddResourceTemplate(searchCSVTemplate, s.handleReadSearchResultCSV)
+
+	// And as newline-delimited JSON for streaming ingestion
+	searchNDJSONTemplate := mcp.NewResourceTemplate(
+		searchNDJSONResourcePrefix+"{"+searchIDParam+"}",
+		"MasaX Search Result (NDJSON)",
+		mcp.WithTemplateDescription("The results of a specific Masa X API search as newline-delimited JSON, one result per line, followed by a final {\"_metadata\": ...} line."),
+		mcp.WithTemplateMIMEType(ndjsonMimeType),
+	)
+
+	s.AddResourceTemplate(searchNDJSONTemplate, s.handleReadSearchResultNDJSON)
+
+	// A directory of the stored searches; each one is also listed as a
+	// resource of its own as it is stored
+	searchListResource := mcp.NewResource(
+		searchListResourceURI,
+		"MasaX Recent Searches",
+		mcp.WithResourceDescription("The stored searches whose results can be read, most recent first, with their search_id, query, result count, storage time and resource URI."),
 		mcp.WithMIMEType(jsonMimeType),
 	)
 
-	s.AddResource(searchResultResource, s.handleReadSearchResult)
+	s.AddResource(searchListResource, s.handleListSearches)
 
-	return nil
+	return s.checkToolConfig()
 }
 
 // handleMasaXSearch uses mcp.CallToolRequest and now returns the result content directly.
 func (s *MCPServer) handleMasaXSearch(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	query, ok := request.Params.Arguments["query"].(string)
-	if !ok || query == "" {
-		return mcp.NewToolResultError("Missing or invalid 'query' argument"), nil
+	query, err := queryArg(request, "query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	maxResults, err := s.maxResultsArg(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	params := searchParams{Query: query, MaxResults: maxResults}
+
+	if params.StartTime, err = timeArg(request, "start_time"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if params.EndTime, err = timeArg(request, "end_time"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if !params.StartTime.IsZero() && !params.EndTime.IsZero() && !params.StartTime.Before(params.EndTime) {
+		return mcp.NewToolResultError("'start_time' must be before 'end_time'"), nil
+	}
+	sortBy, _ := request.Params.Arguments["sort_by"].(string)
+	if params.SortBy, err = masax.ParseSortOrder(sortBy); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid 'sort_by' argument: %v", err)), nil
 	}
 
-	// Extract optional max_results (default to 0 or a reasonable value if needed)
-	maxResults := 0 // Default to no limit specified or handle as per API needs
-	if val, exists := request.Params.Arguments["max_results"]; exists {
-		if num, ok := val.(float64); ok { // JSON numbers often decode as float64
-			maxResults = int(num)
+	if lang, _ := request.Params.Arguments["lang"].(string); lang != "" {
+		if params.Lang, err = masax.ParseLanguage(lang); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid 'lang' argument: %v", err)), nil
 		}
 	}
+	if place, _ := request.Params.Arguments["place"].(string); place != "" {
+		if params.Place = strings.TrimSpace(place); params.Place == "" || strings.Contains(params.Place, `"`) {
+			return mcp.NewToolResultError("Invalid 'place' argument: must be a place name without double quotes"), nil
+		}
+	}
+	if geo, _ := request.Params.Arguments["geo"].(string); geo != "" {
+		point, err := masax.ParsePointRadius(geo)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid 'geo' argument: %v", err)), nil
+		}
+		params.Geo = &point
+	}
+	if params.MinLikes, err = nonNegativeIntArg(request, "min_likes"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if params.MinRetweets, err = nonNegativeIntArg(request, "min_retweets"); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if _, ok := request.Params.Arguments["exclude_terms"]; ok {
+		if params.ExcludeTerms, err = stringSliceArg(request, "exclude_terms"); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if len(params.ExcludeTerms) > maxExcludeTerms {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid 'exclude_terms' argument: at most %d terms are allowed", maxExcludeTerms)), nil
+		}
+	}
+	params.ExcludeWholeWords = boolArg(request, "exclude_whole_words", false)
+	params.VerifiedOnly = boolArg(request, "verified_only", false)
+	params.ExpandSynonyms = boolArg(request, "expand_synonyms", false)
+	if synonyms, _ := request.Params.Arguments["synonyms"].(string); synonyms != "" {
+		if params.Synonyms, err = masax.ParseSynonyms(synonyms); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid 'synonyms' argument: %v", err)), nil
+		}
+	}
+	params.Dedupe = boolArg(request, "dedupe", false)
+	params.Sentiment = boolArg(request, "sentiment", false)
+	params.Entities = boolArg(request, "entities", false)
+	params.Compact = boolArg(request, "compact", false)
+	fields, _ := request.Params.Arguments["fields"].(string)
+	if params.Fields, err = parseFields(fields); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	metrics, _ := request.Params.Arguments["metrics"].(string)
+	if params.Metrics, err = parseMetrics(metrics); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	params.DryRun = boolArg(request, "dry_run", false)
+	params.ExcludeRetweets = !boolArg(request, "include_retweets", true)
+	params.ExcludeReplies = !boolArg(request, "include_replies", true)
+
+	fmt.Printf("Received search request for query: '%s', max_results: %d\n", query, params.MaxResults)
+
+	return s.runSearch(ctx, params)
+}
+
+// maxResultsUsage describes the bounds and default of the max_results
+// argument for tool schemas.
+func (s *MCPServer) maxResultsUsage() string {
+	if s.defaultMaxResults == 0 {
+		return fmt.Sprintf("up to %d (optional)", s.maxResultsLimit)
+	}
+	return fmt.Sprintf("up to %d (optional, default %d; 0 leaves the count to the API)", s.maxResultsLimit, s.defaultMaxResults)
+}
 
-	fmt.Printf("Received search request for query: '%s', max_results: %d\n", query, maxResults)
+// boolArg extracts an optional boolean argument, returning def if absent.
+func boolArg(request mcp.CallToolRequest, name string, def bool) bool {
+	if v, ok := request.Params.Arguments[name].(bool); ok {
+		return v
+	}
+	return def
+}
+
+// nonNegativeIntArg extracts an optional non-negative integer argument,
+// defaulting to 0.
+func nonNegativeIntArg(request mcp.CallToolRequest, name string) (int, error) {
+	val, exists := request.Params.Arguments[name]
+	if !exists {
+		return 0, nil
+	}
+	num, ok := val.(float64)
+	if !ok || num < 0 || num != float64(int(num)) {
+		return 0, fmt.Errorf("Invalid '%s' argument: must be a non-negative integer", name)
+	}
+	return int(num), nil
+}
+
+// queryArg extracts a required query argument, normalized by
+// normalizeQueryArg.
+func queryArg(request mcp.CallToolRequest, name string) (string, error) {
+	raw, _ := request.Params.Arguments[name].(string)
+	return normalizeQueryArg(name, raw)
+}
+
+// normalizeQueryArg normalizes the query given as argument name with
+// masax.NormalizeQuery. Every tool normalizes its queries up front, so
+// equivalent queries share a search_id whichever tool runs them.
+func normalizeQueryArg(name, raw string) (string, error) {
+	query, err := masax.NormalizeQuery(raw, 0)
+	if err != nil {
+		return "", fmt.Errorf("Missing or invalid '%s' argument", name)
+	}
+	return query, nil
+}
+
+// timeArg parses an optional RFC3339 timestamp or YYYY-MM-DD date argument.
+// It returns the zero time if the argument is absent or empty.
+func timeArg(request mcp.CallToolRequest, name string) (time.Time, error) {
+	raw, _ := request.Params.Arguments[name].(string)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	return parseTimeArg(name, raw)
+}
+
+// parseTimeArg parses raw, the value of the argument name, as an RFC3339
+// timestamp or a date.
+func parseTimeArg(name, raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("Invalid '%s' argument '%s': expected an RFC3339 timestamp (e.g. 2024-01-02T15:04:05Z) or a date (YYYY-MM-DD)", name, raw)
+}
+
+// maxResultsArg extracts and validates the optional max_results argument,
+// defaulting to the server's default max_results.
+func (s *MCPServer) maxResultsArg(request mcp.CallToolRequest) (int, error) {
+	val, exists := request.Params.Arguments["max_results"]
+	if !exists {
+		return s.defaultMaxResults, nil
+	}
+	num, ok := val.(float64) // JSON numbers often decode as float64
+	if !ok {
+		return 0, fmt.Errorf("Invalid 'max_results' argument: must be a number")
+	}
+	if num < 0 || num > float64(s.maxResultsLimit) {
+		return 0, fmt.Errorf("Invalid 'max_results' argument %v: must be between 0 and %d", num, s.maxResultsLimit)
+	}
+	if num != float64(int(num)) {
+		return 0, fmt.Errorf("Invalid 'max_results' argument %v: must be a whole number", num)
+	}
+	return int(num), nil
+}
+
+// search runs the search described by params, through SearchAll if it needs
+// more than one page, reporting progress if the tool call asked for it.
+func (s *MCPServer) search(ctx context.Context, params searchParams) (*masax.SearchResponse, error) {
+	opts := append(params.searchOptions(), s.progressOptions(ctx)...)
+	if params.MaxResults > s.pageSize {
+		return s.masaClient.SearchAll(ctx, params.Query, params.MaxResults, opts...)
+	}
+	return s.masaClient.Search(ctx, params.Query, params.MaxResults, opts...)
+}
+
+// runSearch executes a search and returns the results as an embedded resource,
+// storing them so the resource URI can be read back later.
+func (s *MCPServer) runSearch(ctx context.Context, params searchParams) (*mcp.CallToolResult, error) {
+	query := params.Query
 
 	// 1. Call the actual Masa X API using s.masaClient
-	searchResponse, err := s.masaClient.Search(ctx, query, maxResults)
+	searchResponse, err := s.search(ctx, params)
 	if err != nil {
 		// Return API errors as tool errors for the LLM
-		errMsg := fmt.Sprintf("Masa X API error: %v", err)
-		log.Println(errMsg) // Log the error server-side too
-		return mcp.NewToolResultError(errMsg), nil
+		logSearchError(ctx, err) // Log the error server-side too
+		return searchErrorResult(err), nil
+	}
+	if searchResponse.DryRun != nil {
+		return dryRunResult(searchResponse.DryRun)
 	}
 
-	// 2. Marshal the successful response to JSON
-	jsonData, err := json.MarshalIndent(searchResponse, "", "  ") // Use MarshalIndent for readability
+	// 2. Store the response and build the resource content the tool returns
+	searchID, resultContents, err := s.storeSearchResult(params, searchResponse)
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to marshal Masa X response: %v", err)
 		log.Println(errMsg)
 		return mcp.NewToolResultError(errMsg), nil // Internal server error
 	}
 
-	// 3. Generate a unique search_id if needed for the resource URI.
-	//    For simplicity, let's just use the query for now, but UUID or hash is better.
-	searchID := query // Simplistic ID
-	resultURI := searchResultResourcePrefix + searchID
+	text := fmt.Sprintf("Masa X search results for query: '%s' (also available as CSV at %s and NDJSON at %s)",
+		query, searchCSVResourcePrefix+searchID, searchNDJSONResourcePrefix+searchID)
+	if params.VerifiedOnly && len(searchResponse.Items) == 0 {
+		text += "\nNo posts by verified authors were found. If the API doesn't report author_verified, verified_only always returns nothing."
+	}
+	if token := searchResponse.Metadata.NextToken; token != "" && masax.ValidateNextToken(token) == nil {
+		text += "\nMore results are available at " + nextTokenURI(searchID, token)
+	}
 
-	// 4. Construct the resource content that the tool will return
-	resultContents := mcp.TextResourceContents{
-		URI:      resultURI, // URI representing this specific result
-		MIMEType: jsonMimeType,
-		Text:     string(jsonData), // The actual JSON string from API
+	// 3. Cut the embedded results down to the budget if needed
+	if len(resultContents.Text) > s.resultBudget {
+		jsonData, kept, err := s.fitResults(searchResponse, params)
+		if err != nil {
+			errMsg := fmt.Sprintf("Failed to marshal Masa X response: %v", err)
+			log.Println(errMsg)
+			return mcp.NewToolResultError(errMsg), nil
+		}
+		resultContents.Text = string(jsonData)
+		text += "\n" + truncationNote(kept, searchResponse, resultContents.URI)
 	}
 
-	// 5. Return the result using NewToolResultResource, embedding the content
-	return mcp.NewToolResultResource(
-		fmt.Sprintf("Masa X search results for query: '%s'", query),
-		resultContents,
-	), nil
+	// 4. Return the result using NewToolResultResource, embedding the content
+	return mcp.NewToolResultResource(text, resultContents), nil
+}
+
+// dryRunResult reports the request a dry-run search would have sent. Nothing
+// is stored, so no resource URI is returned.
+func dryRunResult(dryRun *masax.DryRunRequest) (*mcp.CallToolResult, error) {
+	jsonData, err := json.MarshalIndent(dryRun, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal dry-run request: %v", err)), nil
+	}
+	return mcp.NewToolResultText("DRY RUN: no request was sent to the Masa X API. It would have sent:\n" + string(jsonData)), nil
+}
+
+// storeSearchResult stores a response under a stable ID, so the resource URI
+// is valid for any query and reads return exactly these results, and renders
+// it as JSON resource content.
+func (s *MCPServer) storeSearchResult(params searchParams, searchResponse *masax.SearchResponse) (string, mcp.TextResourceContents, error) {
+	jsonData, err := params.marshalResults(annotatedResponse{searchResponse, fetchedFreshness(searchResponse)})
+	if err != nil {
+		return "", mcp.TextResourceContents{}, err
+	}
+
+	searchID := s.saveSearch(params, searchResponse)
+	return searchID, mcp.TextResourceContents{
+		URI:      searchResultResourcePrefix + searchID, // URI representing this specific result
+		MIMEType: jsonMimeType,
+		Text:     string(jsonData), // The actual JSON string from API
+	}, nil
 }
 
 // handleReadSearchResult uses mcp.ReadResourceRequest and returns []mcp.ResourceContents.
-// This handler might become less relevant if the tool always returns full results.
-// For now, it simulates fetching based on ID (which is just the query in this simple version).
+// It serves the response stored by the search tool under the given search_id,
+// so reads are consistent with the tool output and don't re-hit the API.
 func (s *MCPServer) handleReadSearchResult(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	searchID, ok := request.Params.Arguments[searchIDParam].(string) // ID is passed via arguments
-	if !ok || searchID == "" {
-		return nil, fmt.Errorf("missing '%s' argument in resource request for URI %s", searchIDParam, request.Params.URI)
+	stored, err := s.storedSearchFor(request)
+	if err != nil {
+		return nil, err
 	}
+	searchID := stored.id
 
-	fmt.Printf("Received request to read search results for id/query: %s\n", searchID)
+	fmt.Printf("Received request to read search results for id: %s (query: '%s')\n", searchID, stored.params.Query)
 
-	// Simulate re-fetching based on the ID (which is the query here)
-	// In a real scenario, might query a cache or re-run the search
-	searchResponse, err := s.masaClient.Search(ctx, searchID, 0) // Assume default maxResults for direct fetch
-	if err != nil {
-		// Return API errors - Resource not found might be appropriate here too
-		errMsg := fmt.Sprintf("Failed to retrieve results for id '%s': %v", searchID, err)
-		log.Println(errMsg)
-		// Consider returning MCP error RESOURCE_NOT_FOUND if applicable
-		// For now, just return nil content, error indicates failure
-		return nil, fmt.Errorf(errMsg)
+	// Serve a single page if one was requested, otherwise all results
+	var payload interface{} = annotatedResponse{stored.response, storedFreshness(stored.response)}
+	if nextToken := resourceArg(request, nextTokenParam); nextToken != "" {
+		if resourceArg(request, pageParam) != "" || resourceArg(request, pageSizeParam) != "" {
+			return nil, fmt.Errorf("'%s' can't be combined with '%s' or '%s'", nextTokenParam, pageParam, pageSizeParam)
+		}
+		if payload, err = s.fetchLivePage(ctx, stored, nextToken); err != nil {
+			return nil, err
+		}
+	} else if rawPage, rawSize := resourceArg(request, pageParam), resourceArg(request, pageSizeParam); rawPage != "" || rawSize != "" {
+		page, err := pageArg(rawPage, pageParam, 1)
+		if err != nil {
+			return nil, err
+		}
+		pageSize, err := pageArg(rawSize, pageSizeParam, defaultPageSize)
+		if err != nil {
+			return nil, err
+		}
+		if pageSize > maxPageSize {
+			return nil, fmt.Errorf("invalid '%s' parameter %d: must be at most %d", pageSizeParam, pageSize, maxPageSize)
+		}
+		if payload, err = pageOf(stored, page, pageSize); err != nil {
+			return nil, err
+		}
 	}
 
-	// Marshal the successful response to JSON
-	jsonData, err := json.MarshalIndent(searchResponse, "", "  ")
+	// Marshal the stored response to JSON
+	jsonData, err := stored.params.marshalResults(payload)
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to marshal Masa X response for id '%s': %v", searchID, err)
 		log.Println(errMsg)
@@ -173,3 +947,113 @@ func (s *MCPServer) handleReadSearchResult(ctx context.Context, request mcp.Read
 		},
 	}, nil
 }
+
+// logSearchError logs a client error along with the HTTP status and
+// diagnostic headers (e.g. rate-limit counters) when the API returned one.
+func logSearchError(ctx context.Context, err error) {
+	id := masax.RequestIDFromContext(ctx)
+	var apiErr *masax.APIError
+	if errors.As(err, &apiErr) {
+		log.Printf("Masa X API error [request %s]: %v (status %d, headers %v)", id, err, apiErr.StatusCode, apiErr.Header)
+		return
+	}
+	log.Printf("Masa X API error [request %s]: %v", id, err)
+}
+
+// toolErrorMessage maps a Masa X client error to a message suited to the LLM,
+// distinguishing failures it can act on (e.g. waiting out a rate limit) from
+// ones it cannot.
+func toolErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, masax.ErrUnauthorized), errors.Is(err, masax.ErrForbidden):
+		return fmt.Sprintf("Masa X API rejected the server's credentials; this cannot be fixed by retrying: %v", err)
+	case errors.Is(err, masax.ErrRateLimited):
+		return fmt.Sprintf("Masa X API rate limit exceeded; wait before searching again: %v", err)
+	case errors.Is(err, masax.ErrUnavailable):
+		return fmt.Sprintf("Masa X API is temporarily unavailable; try again later: %v", err)
+	case errors.Is(err, masax.ErrCircuitOpen):
+		return fmt.Sprintf("Masa X API has been failing, so searches are paused; try again later: %v", err)
+	case errors.Is(err, masax.ErrInvalidQuery):
+		return fmt.Sprintf("Invalid 'query' argument: %v", err)
+	case errors.Is(err, masax.ErrNotSupported):
+		return fmt.Sprintf("Not supported by the Masa X API: %v", err)
+	}
+	var apiErr *masax.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+		return fmt.Sprintf("Masa X API rejected the search request; check the query and arguments: %v", err)
+	}
+	return fmt.Sprintf("Masa X API error: %v", err)
+}
+
+// handleReadSearchResultCSV serves the stored results for a search_id as CSV.
+func (s *MCPServer) handleReadSearchResultCSV(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	stored, err := s.storedSearchFor(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := masax.WriteCSV(&buf, stored.response); err != nil {
+		return nil, fmt.Errorf("failed to render CSV for id '%s': %w", stored.id, err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: csvMimeType,
+			Text:     buf.String(),
+		},
+	}, nil
+}
+
+// handleReadSearchResultNDJSON serves the stored results for a search_id as
+// newline-delimited JSON.
+func (s *MCPServer) handleReadSearchResultNDJSON(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	stored, err := s.storedSearchFor(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := masax.WriteNDJSON(&buf, stored.response, true); err != nil {
+		return nil, fmt.Errorf("failed to render NDJSON for id '%s': %w", stored.id, err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: ndjsonMimeType,
+			Text:     buf.String(),
+		},
+	}, nil
+}
+
+// storedSearchFor looks up the stored search named by the search_id argument
+// of a resource request.
+func (s *MCPServer) storedSearchFor(request mcp.ReadResourceRequest) (*storedSearch, error) {
+	searchID := resourceArg(request, searchIDParam)
+	if searchID == "" {
+		return nil, fmt.Errorf("missing '%s' argument in resource request for URI %s", searchIDParam, request.Params.URI)
+	}
+
+	stored, ok := s.searches.get(searchID)
+	if !ok {
+		return nil, fmt.Errorf("no stored results for search id '%s' (URI %s); run %s again: %w",
+			searchID, request.Params.URI, searchToolName, server.ErrResourceNotFound)
+	}
+	return stored, nil
+}
+
+// resourceArg returns a URI template variable from a resource request. The
+// template matcher supplies values as []string; plain strings are accepted too.
+func resourceArg(request mcp.ReadResourceRequest, name string) string {
+	switch v := request.Params.Arguments[name].(type) {
+	case string:
+		return v
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
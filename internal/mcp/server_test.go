@@ -0,0 +1,493 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"masax-mcp/internal/masax"
+	"masax-mcp/internal/masax/masaxtest"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newTestServer returns a server backed by searcher.
+func newTestServer(t *testing.T, searcher masax.Searcher, opts ...ServerOption) *MCPServer {
+	t.Helper()
+	s, err := NewServer(searcher, opts...)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	return s
+}
+
+// rpc sends a JSON-RPC request through the server's message handler and
+// decodes its result into result, failing the test on a JSON-RPC error.
+func rpc(t *testing.T, s *MCPServer, method string, params, result interface{}) {
+	t.Helper()
+	msg, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	if err != nil {
+		t.Fatalf("marshaling %s request: %v", method, err)
+	}
+	raw, err := json.Marshal(s.HandleMessage(context.Background(), msg))
+	if err != nil {
+		t.Fatalf("marshaling %s response: %v", method, err)
+	}
+	var resp struct {
+		Result json.RawMessage
+		Error  *struct{ Message string }
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		t.Fatalf("decoding %s response: %v", method, err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("%s: %s", method, resp.Error.Message)
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		t.Fatalf("decoding %s result: %v", method, err)
+	}
+}
+
+// toolResult is the part of a tools/call result the tests inspect.
+type toolResult struct {
+	Meta    map[string]interface{} `json:"_meta"`
+	IsError bool
+	Content []struct {
+		Type     string
+		Text     string
+		Resource struct {
+			URI  string
+			Text string
+		}
+	}
+}
+
+// callTool calls a tool with args and returns its result.
+func callTool(t *testing.T, s *MCPServer, name string, args map[string]interface{}) toolResult {
+	t.Helper()
+	var result toolResult
+	rpc(t, s, "tools/call", map[string]interface{}{"name": name, "arguments": args}, &result)
+	return result
+}
+
+// readResource reads uri and returns the text of its first content.
+func readResource(t *testing.T, s *MCPServer, uri string) string {
+	t.Helper()
+	var result struct {
+		Contents []struct{ URI, Text string }
+	}
+	rpc(t, s, "resources/read", map[string]interface{}{"uri": uri}, &result)
+	if len(result.Contents) == 0 {
+		t.Fatalf("reading %s returned no contents", uri)
+	}
+	return result.Contents[0].Text
+}
+
+func TestSearchIDRoundTrip(t *testing.T) {
+	queries := []string{"AC/DC tour", "#bitcoin price", "rate cut odds", "50% off & free/fast #deal?"}
+
+	responses := make(map[string]*masax.SearchResponse)
+	for i, query := range queries {
+		responses[query] = &masax.SearchResponse{Items: []masax.SearchResult{{ID: fmt.Sprint(i + 1), Text: query}}}
+	}
+	fake := &masaxtest.FakeSearcher{Responses: responses}
+	s := newTestServer(t, fake)
+
+	seen := make(map[string]string)
+	for _, query := range queries {
+		t.Run(query, func(t *testing.T) {
+			result := callTool(t, s, searchToolName, map[string]interface{}{"query": query, "max_results": 5})
+			if result.IsError || len(result.Content) < 2 {
+				t.Fatalf("search failed: %+v", result)
+			}
+			uri := result.Content[1].Resource.URI
+
+			searchID, ok := strings.CutPrefix(uri, searchResultResourcePrefix)
+			if !ok {
+				t.Fatalf("resource URI %q lacks prefix %q", uri, searchResultResourcePrefix)
+			}
+			if searchID == "" || url.PathEscape(searchID) != searchID || strings.ContainsAny(searchID, "/#?") {
+				t.Errorf("search_id %q is not URI-safe", searchID)
+			}
+			if other, dup := seen[searchID]; dup {
+				t.Errorf("queries %q and %q share search_id %s", other, query, searchID)
+			}
+			seen[searchID] = query
+
+			var read masax.SearchResponse
+			if err := json.Unmarshal([]byte(readResource(t, s, uri)), &read); err != nil {
+				t.Fatalf("decoding resource: %v", err)
+			}
+			if len(read.Items) != 1 || read.Items[0].Text != query {
+				t.Errorf("resource items = %+v, want the results for %q", read.Items, query)
+			}
+		})
+	}
+
+	// Every search reached the API with its original query, and reads were
+	// served from the store.
+	calls := fake.Calls()
+	if len(calls) != len(queries) {
+		t.Fatalf("API calls = %d, want %d", len(calls), len(queries))
+	}
+	for i, call := range calls {
+		if call.Query != queries[i] {
+			t.Errorf("call %d query = %q, want %q", i, call.Query, queries[i])
+		}
+	}
+
+	// The same search maps to the same search_id.
+	again := callTool(t, s, searchToolName, map[string]interface{}{"query": queries[0], "max_results": 5})
+	if again.IsError || len(again.Content) < 2 {
+		t.Fatalf("repeated search failed: %+v", again)
+	}
+	if uri := again.Content[1].Resource.URI; seen[strings.TrimPrefix(uri, searchResultResourcePrefix)] != queries[0] {
+		t.Errorf("repeated search got URI %s, not the first search's", uri)
+	}
+}
+
+func TestSearchToolWithFakeSearcher(t *testing.T) {
+	fake := &masaxtest.FakeSearcher{Default: &masax.SearchResponse{Items: []masax.SearchResult{
+		{ID: "1", Text: "first"}, {ID: "2", Text: "second"}, {ID: "3", Text: "third"},
+	}}}
+	s := newTestServer(t, fake, WithDefaultMaxResults(2))
+
+	result := callTool(t, s, searchToolName, map[string]interface{}{"query": "  rates  "})
+	if result.IsError || len(result.Content) < 2 {
+		t.Fatalf("search failed: %+v", result)
+	}
+	if text := result.Content[0].Text; !strings.Contains(text, "'rates'") {
+		t.Errorf("result text %q doesn't name the normalized query", text)
+	}
+	var resp masax.SearchResponse
+	if err := json.Unmarshal([]byte(result.Content[1].Resource.Text), &resp); err != nil {
+		t.Fatalf("decoding embedded results: %v", err)
+	}
+	if len(resp.Items) != 2 || resp.Items[0].ID != "1" || resp.Items[1].ID != "2" {
+		t.Errorf("embedded items = %+v, want the first 2", resp.Items)
+	}
+
+	callTool(t, s, searchToolName, map[string]interface{}{"query": "rates", "max_results": 3})
+	calls := fake.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("API calls = %+v, want 2", calls)
+	}
+	if calls[0].Query != "rates" || calls[0].MaxResults != 2 {
+		t.Errorf("first call = %+v, want the normalized query and the server's default max_results", calls[0])
+	}
+	if calls[1].MaxResults != 3 {
+		t.Errorf("second call max_results = %d, want 3", calls[1].MaxResults)
+	}
+}
+
+func TestSearchToolKeepsQueryWithTimeRange(t *testing.T) {
+	fake := &masaxtest.FakeSearcher{}
+	s := newTestServer(t, fake)
+
+	result := callTool(t, s, searchToolName, map[string]interface{}{
+		"query": "rate cut", "start_time": "2024-05-01", "end_time": "2024-05-03T12:00:00Z",
+	})
+	if result.IsError || len(result.Content) < 2 {
+		t.Fatalf("search failed: %+v", result)
+	}
+	if calls := fake.Calls(); len(calls) != 1 || calls[0].Query != "rate cut" {
+		t.Errorf("API calls = %+v, want the query without date operators", calls)
+	}
+	if text := result.Content[0].Text; !strings.Contains(text, "'rate cut'") {
+		t.Errorf("result text %q doesn't name the query as given", text)
+	}
+}
+
+func TestToolsNormalizeQueries(t *testing.T) {
+	const raw, want = "  rate\n cut \u201cfed\u201d ", `rate cut "fed"`
+	tests := []struct {
+		tool string
+		args map[string]interface{}
+	}{
+		{searchToolName, map[string]interface{}{"query": raw}},
+		{searchBatchToolName, map[string]interface{}{"queries": []interface{}{raw}}},
+		{trendsToolName, map[string]interface{}{"query": raw}},
+		{topAuthorsToolName, map[string]interface{}{"query": raw}},
+		{topToolName, map[string]interface{}{"query": raw}},
+		{compareToolName, map[string]interface{}{"query_a": raw, "query_b": raw}},
+		{pollToolName, map[string]interface{}{"query": raw}},
+		{searchWindowsToolName, map[string]interface{}{"query": raw, "windows": []interface{}{
+			map[string]interface{}{"start": "2024-05-01", "end": "2024-05-02"},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			fake := &masaxtest.FakeSearcher{}
+			s := newTestServer(t, fake)
+
+			if result := callTool(t, s, tt.tool, tt.args); result.IsError {
+				t.Fatalf("%s failed: %+v", tt.tool, result)
+			}
+			calls := fake.Calls()
+			if len(calls) == 0 {
+				t.Fatal("no searches made")
+			}
+			for _, call := range calls {
+				if call.Query != want {
+					t.Errorf("searched %q, want %q", call.Query, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchToolInvalidArguments(t *testing.T) {
+	fake := &masaxtest.FakeSearcher{}
+	s := newTestServer(t, fake)
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"missing query", map[string]interface{}{}, "'query'"},
+		{"max_results too large", map[string]interface{}{"query": "x", "max_results": masax.DefaultMaxResultsLimit + 1}, "'max_results'"},
+		{"bad start_time", map[string]interface{}{"query": "x", "start_time": "yesterday"}, "'start_time'"},
+		{"start after end", map[string]interface{}{"query": "x", "start_time": "2024-05-02T00:00:00Z", "end_time": "2024-05-01T00:00:00Z"}, "'start_time' must be before 'end_time'"},
+		{"bad sort_by", map[string]interface{}{"query": "x", "sort_by": "random"}, "'sort_by'"},
+		{"negative min_likes", map[string]interface{}{"query": "x", "min_likes": -1}, "'min_likes'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := callTool(t, s, searchToolName, tt.args)
+			if !result.IsError || len(result.Content) == 0 || !strings.Contains(result.Content[0].Text, tt.want) {
+				t.Errorf("result = %+v, want an error mentioning %s", result, tt.want)
+			}
+		})
+	}
+	if calls := fake.Calls(); len(calls) != 0 {
+		t.Errorf("API calls = %+v, want none for invalid arguments", calls)
+	}
+}
+
+func TestSearchToolErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		category  string
+		retryable bool
+		want      string
+	}{
+		{
+			name:      "rate limited",
+			err:       &masax.APIError{StatusCode: http.StatusTooManyRequests, Message: "slow down", Header: http.Header{"Retry-After": {"30"}}},
+			category:  errCategoryRateLimited,
+			retryable: true,
+			want:      "rate limit exceeded",
+		},
+		{
+			name:     "unauthorized",
+			err:      &masax.APIError{StatusCode: http.StatusUnauthorized, Message: "bad key"},
+			category: errCategoryAuth,
+			want:     "rejected the server's credentials",
+		},
+		{
+			name:      "unavailable",
+			err:       &masax.APIError{StatusCode: http.StatusServiceUnavailable, Message: "down"},
+			category:  errCategoryTransient,
+			retryable: true,
+			want:      "temporarily unavailable",
+		},
+		{
+			name:     "bad request",
+			err:      &masax.APIError{StatusCode: http.StatusBadRequest, Message: "bad operator"},
+			category: errCategoryInvalidRequest,
+			want:     "rejected the search request",
+		},
+		{
+			name:      "deadline",
+			err:       fmt.Errorf("search: %w", context.DeadlineExceeded),
+			category:  errCategoryTransient,
+			retryable: true,
+			want:      "Masa X API error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &masaxtest.FakeSearcher{Errors: map[string]error{"failing": tt.err}}
+			s := newTestServer(t, fake)
+
+			result := callTool(t, s, searchToolName, map[string]interface{}{"query": "failing"})
+			if !result.IsError || len(result.Content) == 0 {
+				t.Fatalf("result = %+v, want a tool error", result)
+			}
+			if text := result.Content[0].Text; !strings.Contains(text, tt.want) {
+				t.Errorf("error text = %q, want it to contain %q", text, tt.want)
+			}
+			if got := result.Meta["error_category"]; got != tt.category {
+				t.Errorf("error_category = %v, want %s", got, tt.category)
+			}
+			if got := result.Meta["retryable"]; got != tt.retryable {
+				t.Errorf("retryable = %v, want %t", got, tt.retryable)
+			}
+		})
+	}
+
+	fake := &masaxtest.FakeSearcher{Errors: map[string]error{"failing": tests[0].err}}
+	result := callTool(t, newTestServer(t, fake), searchToolName, map[string]interface{}{"query": "failing"})
+	if result.Meta["retry_after"] != "30" || result.Meta["status_code"] != float64(http.StatusTooManyRequests) {
+		t.Errorf("_meta = %v, want the status code and Retry-After", result.Meta)
+	}
+}
+
+func TestGetTweetToolWithFakeSearcher(t *testing.T) {
+	fake := &masaxtest.FakeSearcher{
+		Default: &masax.SearchResponse{Items: []masax.SearchResult{{ID: "101", Text: "from a search"}}},
+		Posts:   map[string]*masax.SearchResult{"202": {ID: "202", Text: "from the API"}},
+	}
+	s := newTestServer(t, fake)
+	callTool(t, s, searchToolName, map[string]interface{}{"query": "anything"})
+
+	tests := []struct {
+		id         string
+		wantSource string
+		wantText   string
+	}{
+		{"101", postSourceStored, "from a search"},
+		{"202", postSourceAPI, "from the API"},
+	}
+	for _, tt := range tests {
+		result := callTool(t, s, getTweetToolName, map[string]interface{}{"id": tt.id})
+		if result.IsError || len(result.Content) == 0 {
+			t.Fatalf("get tweet %s failed: %+v", tt.id, result)
+		}
+		var got getTweetResult
+		if err := json.Unmarshal([]byte(result.Content[0].Text), &got); err != nil {
+			t.Fatalf("decoding result: %v", err)
+		}
+		if got.Source != tt.wantSource || got.Post.Text != tt.wantText {
+			t.Errorf("get tweet %s = %+v, want source %s and text %q", tt.id, got, tt.wantSource, tt.wantText)
+		}
+		if (got.SearchURI != "") != (tt.wantSource == postSourceStored) {
+			t.Errorf("get tweet %s search_uri = %q", tt.id, got.SearchURI)
+		}
+	}
+
+	result := callTool(t, s, getTweetToolName, map[string]interface{}{"id": "303"})
+	if !result.IsError || result.Meta["error_category"] != errCategoryUnsupported {
+		t.Errorf("get tweet of an unknown post = %+v, want an unsupported error", result)
+	}
+}
+
+func TestTopAuthorsToolWithFakeSearcher(t *testing.T) {
+	fake := &masaxtest.FakeSearcher{Default: &masax.SearchResponse{Items: []masax.SearchResult{
+		{ID: "1", AuthorID: "alice", PublicMetrics: masax.PublicMetrics{LikeCount: 5}},
+		{ID: "2", AuthorID: "bob", PublicMetrics: masax.PublicMetrics{LikeCount: 50}},
+		{ID: "3", AuthorID: "alice", PublicMetrics: masax.PublicMetrics{LikeCount: 10}},
+	}}}
+	s := newTestServer(t, fake)
+
+	result := callTool(t, s, topAuthorsToolName, map[string]interface{}{"query": "rates", "top_n": 1})
+	if result.IsError || len(result.Content) == 0 {
+		t.Fatalf("top authors failed: %+v", result)
+	}
+	var report topAuthorsReport
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &report); err != nil {
+		t.Fatalf("decoding report: %v", err)
+	}
+	if report.PostsSeen != 3 || len(report.Authors) != 1 || report.Authors[0].AuthorID != "bob" {
+		t.Errorf("report = %+v, want bob alone out of 3 posts", report)
+	}
+	if !strings.HasPrefix(report.SearchURI, searchResultResourcePrefix) {
+		t.Errorf("search_uri = %q, want a stored search", report.SearchURI)
+	}
+}
+
+func TestHealthToolWithFakeSearcher(t *testing.T) {
+	fake := &masaxtest.FakeSearcher{PingLatency: 42 * time.Millisecond}
+	s := newTestServer(t, fake)
+
+	result := callTool(t, s, healthToolName, nil)
+	if result.IsError || len(result.Content) == 0 {
+		t.Fatalf("health failed: %+v", result)
+	}
+	var status healthStatus
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &status); err != nil {
+		t.Fatalf("decoding status: %v", err)
+	}
+	if status.Status != "ok" || status.LatencyMS != 42 || status.Retries != nil {
+		t.Errorf("status = %+v, want ok, 42ms and no retry stats from the fake", status)
+	}
+
+	fake.PingErr = &masax.APIError{StatusCode: http.StatusUnauthorized, Message: "bad key"}
+	result = callTool(t, s, healthToolName, nil)
+	if !result.IsError || !strings.Contains(result.Content[0].Text, "health check failed") {
+		t.Errorf("health with a failing ping = %+v, want an error", result)
+	}
+}
+
+// testSession is a client session collecting the notifications sent to it.
+type testSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) Initialize()       {}
+func (s *testSession) Initialized() bool { return true }
+func (s *testSession) SessionID() string { return "test-session" }
+
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func TestSearchToolSendsProgressForMultiPageSearches(t *testing.T) {
+	// Serves 10 posts per page, with the offset of the next page as its token
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req masax.SearchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		offset, _ := strconv.Atoi(req.NextToken)
+		resp := masax.SearchResponse{}
+		for i := offset; i < offset+min(req.MaxResults, 10); i++ {
+			resp.Items = append(resp.Items, masax.SearchResult{ID: strconv.Itoa(i + 1), Text: fmt.Sprintf("post %d", i+1)})
+		}
+		resp.Metadata.NextToken = strconv.Itoa(offset + len(resp.Items))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+	client, err := masax.NewClient("test-key", masax.WithBaseURL(srv.URL), masax.WithMaxResultsLimit(10))
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	s := newTestServer(t, client, WithPageSize(10))
+
+	session := &testSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	msg, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": map[string]interface{}{
+		"name":      searchToolName,
+		"arguments": map[string]interface{}{"query": "rates", "max_results": 25},
+		"_meta":     map[string]interface{}{"progressToken": "tok"},
+	}})
+	if err != nil {
+		t.Fatalf("marshaling request: %v", err)
+	}
+	if _, ok := s.HandleMessage(s.WithContext(context.Background(), session), msg).(mcp.JSONRPCResponse); !ok {
+		t.Fatal("tools/call failed")
+	}
+	close(session.notifications)
+
+	var progress []interface{}
+	for n := range session.notifications {
+		if n.Method != progressNotification || n.Params.AdditionalFields["progressToken"] != "tok" {
+			t.Errorf("notification = %+v, want progress for token tok", n)
+			continue
+		}
+		progress = append(progress, n.Params.AdditionalFields["progress"])
+	}
+	if fmt.Sprint(progress) != "[1 2 3]" {
+		t.Errorf("progress = %v, want one notification per page", progress)
+	}
+}
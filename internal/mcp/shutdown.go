@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// lifecycle tracks in-flight tool calls so shutdown can wait for them.
+type lifecycle struct {
+	mu           sync.Mutex
+	shuttingDown bool
+	inFlight     sync.WaitGroup
+}
+
+// track wraps a tool handler so its calls are counted as in flight, and new
+// calls are refused once shutdown has begun. Each call gets a request ID, so
+// the API requests it makes and the errors it logs can be correlated, and
+// carries the call's progress token, if any.
+func (s *MCPServer) track(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.lifecycle.mu.Lock()
+		if s.lifecycle.shuttingDown {
+			s.lifecycle.mu.Unlock()
+			return mcp.NewToolResultError("Server is shutting down; please retry shortly"), nil
+		}
+		s.lifecycle.inFlight.Add(1)
+		s.lifecycle.mu.Unlock()
+		defer s.lifecycle.inFlight.Done()
+
+		ctx = masax.ContextWithRequestID(ctx, masax.NewRequestID())
+		ctx = withProgressToken(ctx, request)
+		return handler(ctx, request)
+	}
+}
+
+// Shutdown stops accepting tool calls and waits for in-flight calls to
+// finish. It returns ctx.Err() if ctx is done first; the caller should then
+// cancel the context passed to the transport to abort the remaining calls.
+func (s *MCPServer) Shutdown(ctx context.Context) error {
+	s.lifecycle.mu.Lock()
+	s.lifecycle.shuttingDown = true
+	s.lifecycle.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.lifecycle.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
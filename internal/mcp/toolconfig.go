@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolConfig holds the deployment's tool overrides.
+type toolConfig struct {
+	disabled     map[string]bool
+	descriptions map[string]string
+}
+
+// WithDisabledTools leaves the named tools unregistered, e.g.
+// "masa_x_search_batch", so clients never see them. NewServer returns an
+// error for names that aren't tools of this server.
+func WithDisabledTools(names ...string) ServerOption {
+	return func(s *MCPServer) {
+		for _, name := range names {
+			if name = strings.TrimSpace(name); name != "" {
+				if s.tools.disabled == nil {
+					s.tools.disabled = make(map[string]bool)
+				}
+				s.tools.disabled[name] = true
+			}
+		}
+	}
+}
+
+// WithToolDescription replaces the description clients see for the named
+// tool, e.g. to steer a model towards or away from it. NewServer returns an
+// error for names that aren't tools of this server.
+func WithToolDescription(name, description string) ServerOption {
+	return func(s *MCPServer) {
+		if name == "" || description == "" {
+			return
+		}
+		if s.tools.descriptions == nil {
+			s.tools.descriptions = make(map[string]string)
+		}
+		s.tools.descriptions[name] = description
+	}
+}
+
+// addTool registers tool with its handler wrapped by track, applying the
+// configured description override, unless the tool is disabled.
+func (s *MCPServer) addTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	s.toolNames = append(s.toolNames, tool.Name)
+	if s.tools.disabled[tool.Name] {
+		return
+	}
+	if description, ok := s.tools.descriptions[tool.Name]; ok {
+		tool.Description = description
+	}
+	s.AddTool(tool, s.track(handler))
+}
+
+// checkToolConfig reports tool overrides naming unknown tools, which are
+// most likely typos. It runs after all tools have been added.
+func (s *MCPServer) checkToolConfig() error {
+	known := make(map[string]bool, len(s.toolNames))
+	for _, name := range s.toolNames {
+		known[name] = true
+	}
+	var unknown []string
+	for name := range s.tools.disabled {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	for name := range s.tools.descriptions {
+		if !known[name] && !s.tools.disabled[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown tool(s) %s in tool configuration (tools: %s)",
+			strings.Join(unknown, ", "), strings.Join(s.toolNames, ", "))
+	}
+	return nil
+}
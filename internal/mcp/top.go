@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// topPostReport is the JSON payload returned by the top post tool.
+type topPostReport struct {
+	Query     string                  `json:"query"`
+	PostsSeen int                     `json:"posts_analyzed"`
+	SearchURI string                  `json:"search_uri"`
+	Weights   masax.EngagementWeights `json:"weights"`
+	Score     float64                 `json:"score"`
+	Post      masax.SearchResult      `json:"post"`
+}
+
+// engagementWeighter is implemented by searchers with configured engagement
+// weights, such as *masax.Client.
+type engagementWeighter interface {
+	EngagementWeights() masax.EngagementWeights
+}
+
+// handleMasaXTop runs a search and returns the single result with the
+// highest weighted engagement. Weights not given default to the searcher's.
+func (s *MCPServer) handleMasaXTop(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := queryArg(request, "query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	maxResults, err := s.maxResultsArg(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	weights := masax.DefaultEngagementWeights
+	if ew, ok := s.masaClient.(engagementWeighter); ok {
+		weights = ew.EngagementWeights()
+	}
+	for name, weight := range map[string]*float64{
+		"like_weight":    &weights.Likes,
+		"retweet_weight": &weights.Retweets,
+		"reply_weight":   &weights.Replies,
+		"quote_weight":   &weights.Quotes,
+	} {
+		if *weight, err = weightArg(request, name, *weight); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	log.Printf("Received top post request for query: '%s', max_results: %d", query, maxResults)
+
+	params := searchParams{Query: query, MaxResults: maxResults}
+	searchResponse, err := s.search(ctx, params)
+	if err != nil {
+		logSearchError(ctx, err)
+		return searchErrorResult(err), nil
+	}
+	top, found := masax.TopResult(searchResponse.Items, weights)
+	if !found {
+		return mcp.NewToolResultText(fmt.Sprintf("No posts found for query '%s'.", query)), nil
+	}
+	searchID := s.saveSearch(params, searchResponse)
+
+	jsonData, err := json.MarshalIndent(topPostReport{
+		Query:     query,
+		PostsSeen: len(searchResponse.Items),
+		SearchURI: searchResultResourcePrefix + searchID,
+		Weights:   weights,
+		Score:     weights.Score(top.PublicMetrics),
+		Post:      top,
+	}, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal post: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// weightArg extracts an optional non-negative engagement weight argument,
+// returning def if absent.
+func weightArg(request mcp.CallToolRequest, name string, def float64) (float64, error) {
+	val, exists := request.Params.Arguments[name]
+	if !exists {
+		return def, nil
+	}
+	num, ok := val.(float64)
+	if !ok || !(num >= 0) || math.IsInf(num, 0) {
+		return 0, fmt.Errorf("Invalid '%s' argument: must be a non-negative number", name)
+	}
+	return num, nil
+}
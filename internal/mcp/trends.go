@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const defaultTrendsTopN = 10
+
+// trendsReport is the JSON payload returned by the trends tool.
+type trendsReport struct {
+	Query     string `json:"query"`
+	PostsSeen int    `json:"posts_analyzed"`
+	SearchURI string `json:"search_uri"`
+	masax.Trends
+}
+
+// handleMasaXTrends runs a search and returns the most frequent hashtags,
+// mentions and URLs in the results.
+func (s *MCPServer) handleMasaXTrends(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := queryArg(request, "query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	maxResults, err := s.maxResultsArg(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	topN, err := nonNegativeIntArg(request, "top_n")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if topN == 0 {
+		topN = defaultTrendsTopN
+	}
+
+	log.Printf("Received trends request for query: '%s', max_results: %d", query, maxResults)
+
+	params := searchParams{Query: query, MaxResults: maxResults}
+	searchResponse, err := s.search(ctx, params)
+	if err != nil {
+		logSearchError(ctx, err)
+		return searchErrorResult(err), nil
+	}
+	searchID := s.saveSearch(params, searchResponse)
+
+	jsonData, err := json.MarshalIndent(trendsReport{
+		Query:     query,
+		PostsSeen: len(searchResponse.Items),
+		SearchURI: searchResultResourcePrefix + searchID,
+		Trends:    masax.AggregateTrends(searchResponse.Items, topN),
+	}, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal trends: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// usageReporter is implemented by searchers that track API quota, such as
+// *masax.Client.
+type usageReporter interface {
+	Usage(ctx context.Context) (*masax.Usage, error)
+}
+
+// handleMasaXUsage reports the API quota seen on the most recent response.
+// Unknown usage is reported as a plain message rather than an error.
+func (s *MCPServer) handleMasaXUsage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	reporter, ok := s.masaClient.(usageReporter)
+	if !ok {
+		return mcp.NewToolResultText("Masa X API usage is unknown: this server's client doesn't track quota."), nil
+	}
+	usage, err := reporter.Usage(ctx)
+	if errors.Is(err, masax.ErrUsageUnknown) {
+		return mcp.NewToolResultText(fmt.Sprintf("Masa X API usage is unknown: the API hasn't reported rate-limit headers yet. Run %s and check again; if it stays unknown, the API doesn't report quota.", searchToolName)), nil
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get Masa X API usage: %v", err)), nil
+	}
+
+	jsonData, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal usage: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
@@ -0,0 +1,35 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// handleMasaXSearchByUser searches posts authored by a single account by
+// translating the username into a from: query operator, which also
+// validates it.
+func (s *MCPServer) handleMasaXSearchByUser(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	username, _ := request.Params.Arguments["username"].(string)
+	if strings.TrimSpace(username) == "" {
+		return mcp.NewToolResultError("Missing or invalid 'username' argument"), nil
+	}
+	query, err := masax.NewQuery().From(username).Build()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid 'username' argument: %v", err)), nil
+	}
+
+	maxResults, err := s.maxResultsArg(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	log.Printf("Received user search request for username: '%s', max_results: %d", username, maxResults)
+
+	return s.runSearch(ctx, searchParams{Query: query, MaxResults: maxResults})
+}
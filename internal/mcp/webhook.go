@@ -0,0 +1,216 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	webhookAttempts       = 3
+	webhookBaseDelay      = time.Second
+	webhookTimeout        = 10 * time.Second // Per delivery attempt
+	webhookDeadline       = 5 * time.Minute  // For the search and all delivery attempts
+	webhookDeliveryHeader = "X-Masax-Delivery-Id"
+	idempotencyKeyHeader  = "Idempotency-Key"
+	maxWebhookErrorBody   = 512
+)
+
+// idempotencyKeyPattern matches acceptable idempotency keys: up to 255
+// characters that are safe in a header value.
+var idempotencyKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]{1,255}$`)
+
+// webhookPayload is the JSON body POSTed to a webhook callback URL.
+type webhookPayload struct {
+	DeliveryID     string                `json:"delivery_id"`
+	IdempotencyKey string                `json:"idempotency_key"`
+	Query          string                `json:"query"`
+	Status         string                `json:"status"` // "ok" or "error"
+	Error          string                `json:"error,omitempty"`
+	SearchURI      string                `json:"search_uri,omitempty"`
+	Results        *masax.SearchResponse `json:"results,omitempty"`
+}
+
+// webhookDelivery identifies one logical delivery. Every attempt carries the
+// same IDs, so receivers can drop duplicates caused by retries.
+type webhookDelivery struct {
+	id             string
+	idempotencyKey string // The caller's key, or id if none was given
+	callbackURL    string
+}
+
+// WithWebhookAllowlist enables the webhook search tool, which POSTs search
+// results to a callback URL, for callback hosts matching one of hosts. A
+// host matches exactly, ignoring case, or by subdomain if the entry starts
+// with "*.", e.g. "*.example.com". Callbacks to any other host are refused,
+// so tool calls can't make the server send requests to internal services.
+// Without an allowlist, the tool isn't offered.
+func WithWebhookAllowlist(hosts ...string) ServerOption {
+	return func(s *MCPServer) {
+		for _, host := range hosts {
+			if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+				s.webhookHosts = append(s.webhookHosts, host)
+			}
+		}
+	}
+}
+
+// webhookHTTPClient delivers webhooks. It doesn't follow redirects, which
+// could lead to hosts outside the allowlist.
+var webhookHTTPClient = &http.Client{
+	Timeout: webhookTimeout,
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// validateCallbackURL checks that raw is an absolute http or https URL,
+// without credentials, on an allowlisted host.
+func (s *MCPServer) validateCallbackURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid 'callback_url' argument: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("Invalid 'callback_url' argument: scheme must be http or https, got %q", u.Scheme)
+	}
+	if u.Hostname() == "" || u.User != nil {
+		return nil, fmt.Errorf("Invalid 'callback_url' argument: must have a host and no credentials")
+	}
+	if !s.webhookHostAllowed(u.Hostname()) {
+		return nil, fmt.Errorf("Invalid 'callback_url' argument: host %q is not in the webhook allowlist", u.Hostname())
+	}
+	return u, nil
+}
+
+// webhookHostAllowed reports whether host matches the webhook allowlist.
+func (s *MCPServer) webhookHostAllowed(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, allowed := range s.webhookHosts {
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// handleMasaXSearchWebhook validates the arguments and returns a delivery
+// ID at once, leaving the search and the delivery of its results to the
+// callback URL to run in the background.
+func (s *MCPServer) handleMasaXSearchWebhook(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := queryArg(request, "query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	maxResults, err := s.maxResultsArg(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	rawURL, ok := request.Params.Arguments["callback_url"].(string)
+	if !ok || rawURL == "" {
+		return mcp.NewToolResultError("Missing or invalid 'callback_url' argument"), nil
+	}
+	callback, err := s.validateCallbackURL(rawURL)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	delivery := webhookDelivery{id: masax.NewRequestID(), callbackURL: callback.String()}
+	delivery.idempotencyKey, _ = request.Params.Arguments["idempotency_key"].(string)
+	if delivery.idempotencyKey == "" {
+		delivery.idempotencyKey = delivery.id
+	} else if !idempotencyKeyPattern.MatchString(delivery.idempotencyKey) {
+		return mcp.NewToolResultError("Invalid 'idempotency_key' argument: must be 1-255 letters, digits, '_', '.', ':' or '-'"), nil
+	}
+
+	log.Printf("Received webhook search request for query: '%s', max_results: %d, delivery: %s", query, maxResults, delivery.id)
+
+	// The delivery outlives the tool call, but Shutdown still waits for it.
+	// This call is in flight, so shutdown can't have begun.
+	s.lifecycle.inFlight.Add(1)
+	go func() {
+		defer s.lifecycle.inFlight.Done()
+		bgCtx, cancel := context.WithTimeout(withoutProgressToken(context.WithoutCancel(ctx)), webhookDeadline)
+		defer cancel()
+		s.deliverWebhook(bgCtx, delivery, searchParams{Query: query, MaxResults: maxResults})
+	}()
+
+	return mcp.NewToolResultText(fmt.Sprintf("Search for '%s' started; results will be POSTed to %s with delivery ID %s (also sent as the %s header) and idempotency key %s (sent as the %s header, the same on every retry).",
+		query, callback.Redacted(), delivery.id, webhookDeliveryHeader, delivery.idempotencyKey, idempotencyKeyHeader)), nil
+}
+
+// deliverWebhook runs the search and POSTs its results, or its error, to
+// delivery's callback URL, retrying failed deliveries.
+func (s *MCPServer) deliverWebhook(ctx context.Context, delivery webhookDelivery, params searchParams) {
+	payload := webhookPayload{DeliveryID: delivery.id, IdempotencyKey: delivery.idempotencyKey, Query: params.Query, Status: "ok"}
+	resp, err := s.search(ctx, params)
+	if err != nil {
+		logSearchError(ctx, err)
+		payload.Status = "error"
+		payload.Error = toolErrorMessage(err)
+	} else {
+		payload.Results = resp
+		payload.SearchURI = searchResultResourcePrefix + s.saveSearch(params, resp)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Webhook delivery %s failed: %v", delivery.id, err)
+		return
+	}
+
+	for attempt := 0; ; attempt++ {
+		retry, err := postWebhook(ctx, delivery, body)
+		if err == nil {
+			log.Printf("Webhook delivery %s succeeded", delivery.id)
+			return
+		}
+		if !retry || attempt+1 >= webhookAttempts {
+			log.Printf("Webhook delivery %s failed after %d attempt(s): %v", delivery.id, attempt+1, err)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			log.Printf("Webhook delivery %s abandoned: %v", delivery.id, err)
+			return
+		case <-time.After(webhookBaseDelay << attempt):
+		}
+	}
+}
+
+// postWebhook makes one delivery attempt, reporting whether a failure may
+// succeed on retry: network errors, 429 and 5xx responses.
+func postWebhook(ctx context.Context, delivery webhookDelivery, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", jsonMimeType)
+	req.Header.Set(webhookDeliveryHeader, delivery.id)
+	req.Header.Set(idempotencyKeyHeader, delivery.idempotencyKey)
+	resp, err := webhookHTTPClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookErrorBody))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("callback returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxTimeWindows bounds the windows of one multi-window search, like the
+// queries of a batch.
+const maxTimeWindows = maxBatchQueries
+
+// handleMasaXSearchWindows runs a query over several time windows with
+// bounded parallelism and returns a per-window summary and one embedded
+// resource per successful window. The tool only reports an error if every
+// window fails.
+func (s *MCPServer) handleMasaXSearchWindows(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := queryArg(request, "query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	windows, err := timeWindowsArg(request, "windows")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	maxResults, err := s.maxResultsArg(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	log.Printf("Received windowed search request for query: '%s', %d windows, max_results: %d", query, len(windows), maxResults)
+
+	responses, errs := s.masaClient.SearchWindows(ctx, query, windows, maxResults, batchConcurrency)
+
+	var summary []string
+	var contents []mcp.Content
+	total := 0
+	for i, window := range windows {
+		label := windowLabel(window)
+		if errs[i] != nil {
+			logSearchError(ctx, fmt.Errorf("window %s: %w", label, errs[i]))
+			summary = append(summary, fmt.Sprintf("- %s: failed: %s", label, toolErrorMessage(errs[i])))
+			continue
+		}
+		params := searchParams{Query: query, MaxResults: maxResults, StartTime: window.Start, EndTime: window.End}
+		_, resultContents, err := s.storeSearchResult(params, responses[i])
+		if err != nil {
+			summary = append(summary, fmt.Sprintf("- %s: failed to marshal results: %v", label, err))
+			continue
+		}
+		total += len(responses[i].Items)
+		summary = append(summary, fmt.Sprintf("- %s: %d results at %s", label, len(responses[i].Items), resultContents.URI))
+		contents = append(contents, mcp.NewEmbeddedResource(resultContents))
+	}
+
+	text := fmt.Sprintf("Masa X results for query '%s' by time window (%d of %d windows succeeded, %d results in total):\n%s",
+		query, len(contents), len(windows), total, strings.Join(summary, "\n"))
+	return &mcp.CallToolResult{
+		Content: append([]mcp.Content{mcp.NewTextContent(text)}, contents...),
+		IsError: len(contents) == 0,
+	}, nil
+}
+
+// timeWindowsArg extracts a required array of {"start", "end"} objects,
+// each a valid, non-empty time range.
+func timeWindowsArg(request mcp.CallToolRequest, name string) ([]masax.TimeWindow, error) {
+	raw, ok := request.Params.Arguments[name].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Missing or invalid '%s' argument: must be an array of {\"start\", \"end\"} objects", name)
+	}
+	if len(raw) == 0 || len(raw) > maxTimeWindows {
+		return nil, fmt.Errorf("Invalid '%s' argument: must contain between 1 and %d windows", name, maxTimeWindows)
+	}
+	windows := make([]masax.TimeWindow, 0, len(raw))
+	for i, v := range raw {
+		elem := fmt.Sprintf("%s[%d]", name, i) // Zero-based, as in the schema's array
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Invalid '%s' argument: must be an object with 'start' and 'end'", elem)
+		}
+		var window masax.TimeWindow
+		for _, f := range []struct {
+			field string
+			t     *time.Time
+		}{{"start", &window.Start}, {"end", &window.End}} {
+			field, t := f.field, f.t
+			value, _ := obj[field].(string)
+			if value == "" {
+				return nil, fmt.Errorf("Missing or invalid '%s.%s' argument", elem, field)
+			}
+			var err error
+			if *t, err = parseTimeArg(elem+"."+field, value); err != nil {
+				return nil, err
+			}
+		}
+		if !window.Start.Before(window.End) {
+			return nil, fmt.Errorf("Invalid '%s' argument: start must be before end", elem)
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// windowLabel describes a window for the summary, with dates alone for
+// bounds at midnight UTC.
+func windowLabel(w masax.TimeWindow) string {
+	format := func(t time.Time) string {
+		t = t.UTC()
+		if t.Equal(t.Truncate(24 * time.Hour)) {
+			return t.Format(time.DateOnly)
+		}
+		return t.Format(time.RFC3339)
+	}
+	return format(w.Start) + " to " + format(w.End)
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"masax-mcp/internal/masax"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// SearchMetrics records Prometheus metrics for Masa X searches. Register its
+// Observe method with masax.WithSearchHook.
+type SearchMetrics struct {
+	registry *prometheus.Registry
+	searches *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+}
+
+// NewSearchMetrics creates the search metrics on a dedicated registry.
+func NewSearchMetrics() *SearchMetrics {
+	m := &SearchMetrics{
+		registry: prometheus.NewRegistry(),
+		searches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "masax_searches_total",
+			Help: "Total Masa X searches, by HTTP status and whether served from cache.",
+		}, []string{"status", "cached"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "masax_search_errors_total",
+			Help: "Failed Masa X searches, by error category.",
+		}, []string{"category"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "masax_api_latency_seconds",
+			Help:    "Latency of Masa X API calls, including retries.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"status"}),
+	}
+	m.registry.MustRegister(m.searches, m.errors, m.latency)
+	return m
+}
+
+// Observe records a search event. It satisfies masax.SearchHook.
+func (m *SearchMetrics) Observe(_ context.Context, event masax.SearchEvent) {
+	status := strconv.Itoa(event.StatusCode)
+	m.searches.WithLabelValues(status, strconv.FormatBool(event.Cached)).Inc()
+	if event.Err != nil {
+		m.errors.WithLabelValues(masax.ErrorCategory(event.Err)).Inc()
+	}
+	if !event.Cached {
+		m.latency.WithLabelValues(status).Observe(event.Duration.Seconds())
+	}
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *SearchMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}